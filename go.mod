@@ -3,15 +3,20 @@ module github.com/hashicorp/terraform-schema
 go 1.14
 
 require (
-	github.com/google/go-cmp v0.3.1
+	github.com/agext/levenshtein v1.2.2 // indirect
+	github.com/golang/protobuf v1.4.1
+	github.com/google/go-cmp v0.5.0
 	github.com/hashicorp/go-multierror v1.1.0
 	github.com/hashicorp/go-version v1.2.0
 	github.com/hashicorp/hcl-lang v0.0.0-20201110071249-4e412924f52b
 	github.com/hashicorp/hcl/v2 v2.6.0
-	github.com/hashicorp/terraform-config-inspect v0.0.0-20201102131242-0c45ba392e51
-	github.com/hashicorp/terraform-json v0.7.0
+	github.com/hashicorp/terraform-json v0.10.0
 	github.com/hashicorp/terraform-svchost v0.0.0-20200729002733-f050f53b9734
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/mitchellh/go-wordwrap v1.0.0 // indirect
 	github.com/zclconf/go-cty v1.7.1-0.20201110003513-1338293a79a9
 	github.com/zclconf/go-cty-debug v0.0.0-20191215020915-b22d67c1ba0b
 	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
+	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,36 @@
+package addrs
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ImpliedProviderLocalName returns the local name of the provider
+// implied by the given resource or data source type, i.e. the part
+// of the type name before the first underscore.
+//
+// For example, google_compute_instance implies provider "google"
+// (even if "google-beta" is actually used via the provider meta-argument).
+func ImpliedProviderLocalName(resourceType string) string {
+	if under := strings.Index(resourceType, "_"); under != -1 {
+		return resourceType[:under]
+	}
+	return resourceType
+}
+
+// ResourceProviderConfig returns the provider configuration which
+// a resource or data source of the given type uses.
+//
+// The traversal represents the value of the provider meta-argument
+// (e.g. google.west), which takes precedence if not empty. Otherwise
+// the default configuration of the implied provider is returned.
+func ResourceProviderConfig(resourceType string, providerAttr hcl.Traversal) (LocalProviderConfig, error) {
+	if len(providerAttr) > 0 {
+		return ParseProviderConfigCompact(providerAttr)
+	}
+
+	return LocalProviderConfig{
+		LocalName: ImpliedProviderLocalName(resourceType),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package addrs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestImpliedProviderLocalName(t *testing.T) {
+	testCases := map[string]string{
+		"aws_instance":            "aws",
+		"google_compute_instance": "google",
+		"terraform_remote_state":  "terraform",
+		"null_resource":           "null",
+		"noprefix":                "noprefix",
+		"a_b_c":                   "a",
+	}
+
+	for resourceType, expectedName := range testCases {
+		name := ImpliedProviderLocalName(resourceType)
+		if name != expectedName {
+			t.Fatalf("%s: expected %q, given %q", resourceType, expectedName, name)
+		}
+	}
+}
+
+func TestResourceProviderConfig(t *testing.T) {
+	testCases := []struct {
+		resourceType string
+		providerExpr string
+		expectedCfg  LocalProviderConfig
+		expectErr    bool
+	}{
+		{
+			"aws_instance",
+			"",
+			LocalProviderConfig{LocalName: "aws"},
+			false,
+		},
+		{
+			"google_compute_instance",
+			"google-beta",
+			LocalProviderConfig{LocalName: "google-beta"},
+			false,
+		},
+		{
+			"google_compute_instance",
+			"gb.europe",
+			LocalProviderConfig{LocalName: "gb", Alias: "europe"},
+			false,
+		},
+		{
+			"aws_instance",
+			"aws[0]",
+			LocalProviderConfig{LocalName: "aws"},
+			true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.resourceType), func(t *testing.T) {
+			var traversal hcl.Traversal
+			if tc.providerExpr != "" {
+				var diags hcl.Diagnostics
+				traversal, diags = hclsyntax.ParseTraversalAbs([]byte(tc.providerExpr), "", hcl.InitialPos)
+				if len(diags) > 0 {
+					t.Fatal(diags)
+				}
+			}
+
+			cfg, err := ResourceProviderConfig(tc.resourceType, traversal)
+			if err != nil && !tc.expectErr {
+				t.Fatal(err)
+			}
+			if err == nil && tc.expectErr {
+				t.Fatal("expected error")
+			}
+			if diff := cmp.Diff(tc.expectedCfg, cfg); diff != "" {
+				t.Fatalf("provider config mismatch: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package bodytype converts body schemas into cty types,
+// such that a body (e.g. backend configuration) can be
+// represented as a value of an attribute or a reference.
+package bodytype
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ObjectType converts the given body schema into an object type,
+// where any attributes which are not required and any blocks
+// with no minimum number of items are marked as optional.
+//
+// Nested blocks are represented as collections of objects
+// depending on their type, or (if the type is not set)
+// as an object if at most one block is allowed, or a list otherwise.
+func ObjectType(bs *schema.BodySchema) cty.Type {
+	if bs == nil {
+		return cty.DynamicPseudoType
+	}
+
+	attrTypes := make(map[string]cty.Type, len(bs.Attributes)+len(bs.Blocks))
+	optional := make([]string, 0)
+
+	for name, attr := range bs.Attributes {
+		attrTypes[name] = attr.ValueType
+		if attr.ValueType == cty.NilType {
+			attrTypes[name] = cty.DynamicPseudoType
+		}
+		if !attr.IsRequired {
+			optional = append(optional, name)
+		}
+	}
+
+	for name, block := range bs.Blocks {
+		attrTypes[name] = blockType(block)
+		if block.MinItems == 0 {
+			optional = append(optional, name)
+		}
+	}
+
+	sort.Strings(optional)
+
+	return cty.ObjectWithOptionalAttrs(attrTypes, optional)
+}
+
+func blockType(block *schema.BlockSchema) cty.Type {
+	objType := ObjectType(block.Body)
+
+	switch block.Type {
+	case schema.BlockTypeList:
+		return cty.List(objType)
+	case schema.BlockTypeSet:
+		return cty.Set(objType)
+	case schema.BlockTypeMap:
+		return cty.Map(objType)
+	case schema.BlockTypeObject:
+		return objType
+	}
+
+	if block.MaxItems == 1 {
+		return objType
+	}
+	return cty.List(objType)
+}
@@ -0,0 +1,77 @@
+package bodytype
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestObjectType(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         *schema.BodySchema
+		expectedType cty.Type
+	}{
+		{
+			"nil",
+			nil,
+			cty.DynamicPseudoType,
+		},
+		{
+			"attributes",
+			&schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"name":  {ValueType: cty.String, IsRequired: true},
+					"tags":  {ValueType: cty.Map(cty.String), IsOptional: true},
+					"extra": {IsOptional: true},
+				},
+			},
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name":  cty.String,
+				"tags":  cty.Map(cty.String),
+				"extra": cty.DynamicPseudoType,
+			}, []string{"extra", "tags"}),
+		},
+		{
+			"blocks",
+			&schema.BodySchema{
+				Blocks: map[string]*schema.BlockSchema{
+					"single": {
+						MaxItems: 1,
+						MinItems: 1,
+						Body:     schema.NewBodySchema(),
+					},
+					"untyped": {
+						Body: schema.NewBodySchema(),
+					},
+					"set": {
+						Type: schema.BlockTypeSet,
+						Body: schema.NewBodySchema(),
+					},
+					"map": {
+						Type: schema.BlockTypeMap,
+						Body: schema.NewBodySchema(),
+					},
+				},
+			},
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"single":  cty.EmptyObject,
+				"untyped": cty.List(cty.EmptyObject),
+				"set":     cty.Set(cty.EmptyObject),
+				"map":     cty.Map(cty.EmptyObject),
+			}, []string{"map", "set", "untyped"}),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			givenType := ObjectType(tc.body)
+			if diff := cmp.Diff(tc.expectedType, givenType, cmp.Comparer(cty.Type.Equals)); diff != "" {
+				t.Fatalf("type mismatch: %s", diff)
+			}
+		})
+	}
+}
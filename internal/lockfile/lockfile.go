@@ -0,0 +1,137 @@
+// Package lockfile decodes dependency lock files
+// (.terraform.lock.hcl) introduced in Terraform 0.14.
+package lockfile
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+// FileName is the name of the dependency lock file
+// within the root module directory
+const FileName = ".terraform.lock.hcl"
+
+var rootSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "provider",
+			LabelNames: []string{"source_addr"},
+		},
+	},
+}
+
+var providerSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "version",
+			Required: true,
+		},
+		{
+			Name: "constraints",
+		},
+		{
+			Name: "hashes",
+		},
+	},
+}
+
+// DecodeProviderVersions returns versions of providers locked
+// in the given lock file.
+//
+// Providers with invalid addresses or versions are skipped
+// and reported via diagnostics.
+func DecodeProviderVersions(f *hcl.File) (map[addrs.Provider]*version.Version, hcl.Diagnostics) {
+	versions := make(map[addrs.Provider]*version.Version, 0)
+	if f == nil || f.Body == nil {
+		return versions, nil
+	}
+
+	content, diags := f.Body.Content(rootSchema)
+	declRanges := make(map[addrs.Provider]hcl.Range, 0)
+
+	for _, block := range content.Blocks {
+		addr, ver, blockDiags := decodeProviderBlock(block)
+		diags = append(diags, blockDiags...)
+		if blockDiags.HasErrors() {
+			continue
+		}
+
+		if rng, ok := declRanges[addr]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate provider lock",
+				Detail:   fmt.Sprintf("This lockfile already declared a lock for provider %s at %s.", addr, rng),
+				Subject:  block.LabelRanges[0].Ptr(),
+			})
+			continue
+		}
+
+		declRanges[addr] = block.DefRange
+		versions[addr] = ver
+	}
+
+	return versions, diags
+}
+
+func decodeProviderBlock(block *hcl.Block) (addrs.Provider, *version.Version, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	rawAddr := block.Labels[0]
+	addr, err := addrs.ParseProviderSourceString(rawAddr)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider source address",
+			Detail:   fmt.Sprintf("Provider source address %q is not a valid source string.", rawAddr),
+			Subject:  block.LabelRanges[0].Ptr(),
+		})
+		return addr, nil, diags
+	}
+
+	// lock files only ever contain fully-qualified
+	// addresses as written by Terraform
+	if canonAddr := addr.String(); canonAddr != rawAddr {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Non-normalized provider source address",
+			Detail: fmt.Sprintf("The provider source address for a provider lock must be a normalized, "+
+				"fully-qualified address. This address should be written as %q.", canonAddr),
+			Subject: block.LabelRanges[0].Ptr(),
+		})
+		return addr, nil, diags
+	}
+
+	// unknown arguments are tolerated, since lock files
+	// may be written by newer versions of Terraform
+	content, _, contentDiags := block.Body.PartialContent(providerSchema)
+	diags = append(diags, contentDiags...)
+
+	attr, ok := content.Attributes["version"]
+	if !ok {
+		return addr, nil, diags
+	}
+
+	var rawVersion string
+	valDiags := gohcl.DecodeExpression(attr.Expr, nil, &rawVersion)
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return addr, nil, diags
+	}
+
+	ver, err := version.NewVersion(rawVersion)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider version number",
+			Detail:   fmt.Sprintf("The selected version number for provider %s is invalid: %s.", addr, err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return addr, nil, diags
+	}
+
+	return addr, ver, diags
+}
@@ -0,0 +1,154 @@
+package lockfile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+func TestDecodeProviderVersions(t *testing.T) {
+	src := `# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "3.27.0"
+  constraints = "~> 3.27"
+  hashes = [
+    "h1:SvMIoNmE9ASd4ceDzCHB6FlPKkXK0d5yfr3JwxPTpjE=",
+    "zh:0184e21a4faed7e64cfcc1b30f6b8fa8a50a5c4d41cb8adc5e4e5c8b8fa5d0ac",
+  ]
+}
+
+provider "registry.terraform.io/mycorp/mycloud" {
+  version = "1.0.0-beta1"
+  future  = "argument"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), FileName, hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	versions, diags := DecodeProviderVersions(f)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedVersions := map[addrs.Provider]*version.Version{
+		addrs.NewDefaultProvider("aws"):                                   version.Must(version.NewVersion("3.27.0")),
+		addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "mycloud"): version.Must(version.NewVersion("1.0.0-beta1")),
+	}
+	if diff := cmp.Diff(expectedVersions, versions); diff != "" {
+		t.Fatalf("versions mismatch: %s", diff)
+	}
+}
+
+func TestDecodeProviderVersions_invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		src           string
+		expectedDiags hcl.Diagnostics
+	}{
+		{
+			"invalid address",
+			`provider "registry.terraform.io/hashicorp/aws/foo" {
+  version = "3.27.0"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider source address",
+					Detail:   `Provider source address "registry.terraform.io/hashicorp/aws/foo" is not a valid source string.`,
+					Subject: &hcl.Range{
+						Filename: FileName,
+						Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+						End:      hcl.Pos{Line: 1, Column: 51, Byte: 50},
+					},
+				},
+			},
+		},
+		{
+			"non-normalized address",
+			`provider "hashicorp/aws" {
+  version = "3.27.0"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Non-normalized provider source address",
+					Detail: "The provider source address for a provider lock must be a normalized, fully-qualified " +
+						`address. This address should be written as "registry.terraform.io/hashicorp/aws".`,
+					Subject: &hcl.Range{
+						Filename: FileName,
+						Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+						End:      hcl.Pos{Line: 1, Column: 25, Byte: 24},
+					},
+				},
+			},
+		},
+		{
+			"invalid version",
+			`provider "registry.terraform.io/hashicorp/aws" {
+  version = "three"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider version number",
+					Detail: "The selected version number for provider registry.terraform.io/hashicorp/aws " +
+						"is invalid: Malformed version: three.",
+					Subject: &hcl.Range{
+						Filename: FileName,
+						Start:    hcl.Pos{Line: 2, Column: 13, Byte: 61},
+						End:      hcl.Pos{Line: 2, Column: 20, Byte: 68},
+					},
+				},
+			},
+		},
+		{
+			"duplicate lock",
+			`provider "registry.terraform.io/hashicorp/aws" {
+  version = "3.27.0"
+}
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "3.28.0"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate provider lock",
+					Detail: "This lockfile already declared a lock for provider registry.terraform.io/hashicorp/aws " +
+						"at .terraform.lock.hcl:1,1-47.",
+					Subject: &hcl.Range{
+						Filename: FileName,
+						Start:    hcl.Pos{Line: 4, Column: 10, Byte: 81},
+						End:      hcl.Pos{Line: 4, Column: 47, Byte: 118},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), FileName, hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			_, diags = DecodeProviderVersions(f)
+			if diff := cmp.Diff(tc.expectedDiags, diags); diff != "" {
+				t.Fatalf("diagnostics mismatch: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+var terraformSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "terraform",
+		},
+	},
+}
+
+var experimentsSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "experiments",
+		},
+	},
+}
+
+// DecodeExperiments returns names of experiments enabled
+// via the experiments argument of the terraform block
+func DecodeExperiments(files map[string]*hcl.File) map[string]bool {
+	experiments := make(map[string]bool, 0)
+
+	for _, f := range files {
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, _ := f.Body.PartialContent(terraformSchema)
+		for _, block := range content.Blocks {
+			tfContent, _, _ := block.Body.PartialContent(experimentsSchema)
+			attr, ok := tfContent.Attributes["experiments"]
+			if !ok {
+				continue
+			}
+			exprs, diags := hcl.ExprList(attr.Expr)
+			if diags.HasErrors() {
+				continue
+			}
+			for _, expr := range exprs {
+				if name := hcl.ExprAsKeyword(expr); name != "" {
+					experiments[name] = true
+				}
+			}
+		}
+	}
+
+	return experiments
+}
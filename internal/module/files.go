@@ -0,0 +1,61 @@
+package module
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// ParseFiles parses all configuration files (native and JSON syntax)
+// of the module in the given directory, ignoring files which Terraform
+// ignores (e.g. editor backups).
+//
+// Any parsing errors in individual files are ignored to allow
+// working with incomplete configuration. Files are keyed by name.
+func ParseFiles(dir string) (map[string]*hcl.File, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := hclparse.NewParser()
+	files := make(map[string]*hcl.File, 0)
+
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || isIgnoredFile(name) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		switch {
+		case strings.HasSuffix(name, ".tf"):
+			f, _ := p.ParseHCLFile(path)
+			if f != nil {
+				files[name] = f
+			}
+		case strings.HasSuffix(name, ".tf.json"):
+			f, _ := p.ParseJSONFile(path)
+			if f != nil {
+				files[name] = f
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// IsLocalSource returns true if the given module source
+// refers to a local directory (e.g. ./modules/foo)
+func IsLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+func isIgnoredFile(name string) bool {
+	return strings.HasPrefix(name, ".") || // Unix-like hidden files
+		strings.HasSuffix(name, "~") || // vim
+		strings.HasPrefix(name, "#") && strings.HasSuffix(name, "#") // emacs
+}
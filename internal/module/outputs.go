@@ -0,0 +1,90 @@
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Output represents an output block of a module
+type Output struct {
+	Name        string
+	Description string
+	IsSensitive bool
+
+	DefRange hcl.Range
+}
+
+var outputsSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "output",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var outputBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "description",
+		},
+		{
+			Name: "sensitive",
+		},
+	},
+}
+
+// DecodeOutputs collects outputs declared in the given files,
+// keyed by name
+func DecodeOutputs(files map[string]*hcl.File) (map[string]*Output, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	outputs := make(map[string]*Output, 0)
+
+	for _, name := range sortedFileNames(files) {
+		f := files[name]
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, contentDiags := f.Body.PartialContent(outputsSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			o, oDiags := decodeOutput(block)
+			diags = append(diags, oDiags...)
+			outputs[o.Name] = o
+		}
+	}
+
+	return outputs, diags
+}
+
+func decodeOutput(block *hcl.Block) (*Output, hcl.Diagnostics) {
+	o := &Output{
+		Name:     block.Labels[0],
+		DefRange: block.DefRange,
+	}
+
+	content, _, diags := block.Body.PartialContent(outputBlockSchema)
+
+	if attr, ok := content.Attributes["description"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if isKnownString(val) {
+			o.Description = val.AsString()
+		}
+	}
+
+	if attr, ok := content.Attributes["sensitive"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && isKnownBool(val) {
+			o.IsSensitive = val.True()
+		}
+	}
+
+	return o, diags
+}
+
+func isKnownBool(val cty.Value) bool {
+	return val.Type() == cty.Bool && val.IsKnown() && !val.IsNull()
+}
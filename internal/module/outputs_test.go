@@ -0,0 +1,48 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestDecodeOutputs(t *testing.T) {
+	src := `output "id" {
+  value       = aws_instance.web.id
+  description = "ID of the instance"
+}
+
+output "password" {
+  value     = random_password.main.result
+  sensitive = true
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "outputs.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	outputs, diags := DecodeOutputs(map[string]*hcl.File{
+		"outputs.tf": f,
+	})
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedOutputs := map[string]*Output{
+		"id": {
+			Name:        "id",
+			Description: "ID of the instance",
+		},
+		"password": {
+			Name:        "password",
+			IsSensitive: true,
+		},
+	}
+	if diff := cmp.Diff(expectedOutputs, outputs, cmpopts.IgnoreFields(Output{}, "DefRange")); diff != "" {
+		t.Fatalf("outputs mismatch: %s", diff)
+	}
+}
@@ -0,0 +1,182 @@
+package module
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var requirementsSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "required_version",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "required_providers",
+		},
+	},
+}
+
+// Requirements represents version constraints declared
+// in the terraform block(s) of a module
+type Requirements struct {
+	// CoreVersion contains constraints of all required_version
+	// arguments, all of which must be satisfied
+	CoreVersion []*VersionConstraints
+
+	// Providers contains version constraints of
+	// required providers, keyed by provider address
+	Providers map[addrs.Provider][]*VersionConstraints
+}
+
+// VersionConstraints represents parsed version constraints
+// along with the range of the expression they were declared in
+type VersionConstraints struct {
+	Constraints version.Constraints
+	Range       hcl.Range
+}
+
+// DecodeRequirements decodes the required_version argument
+// and version constraints from the required_providers block.
+//
+// Invalid constraints are skipped and reported via diagnostics.
+// Invalid provider sources are skipped silently, as these
+// are reported when decoding provider references.
+func DecodeRequirements(files map[string]*hcl.File) (*Requirements, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	req := &Requirements{
+		CoreVersion: make([]*VersionConstraints, 0),
+		Providers:   make(map[addrs.Provider][]*VersionConstraints, 0),
+	}
+
+	// Decode files in a stable order to produce stable diagnostics
+	fileNames := make([]string, 0, len(files))
+	for name := range files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	for _, name := range fileNames {
+		f := files[name]
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, _ := f.Body.PartialContent(terraformSchema)
+		for _, block := range content.Blocks {
+			diags = append(diags, req.loadTerraformBlock(block)...)
+		}
+	}
+
+	return req, diags
+}
+
+func (req *Requirements) loadTerraformBlock(block *hcl.Block) hcl.Diagnostics {
+	content, _, diags := block.Body.PartialContent(requirementsSchema)
+
+	if attr, ok := content.Attributes["required_version"]; ok {
+		vc, vcDiags := decodeVersionConstraints(attr.Expr, "Terraform")
+		diags = append(diags, vcDiags...)
+		if vc != nil {
+			req.CoreVersion = append(req.CoreVersion, vc)
+		}
+	}
+
+	for _, rpBlock := range content.Blocks {
+		attrs, attrDiags := rpBlock.Body.JustAttributes()
+		diags = append(diags, attrDiags...)
+
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			addr, vc, reqDiags := decodeProviderRequirement(attrs[name])
+			diags = append(diags, reqDiags...)
+			if vc == nil {
+				continue
+			}
+			req.Providers[addr] = append(req.Providers[addr], vc)
+		}
+	}
+
+	return diags
+}
+
+// decodeProviderRequirement decodes either the legacy (0.12) syntax,
+// e.g. aws = "~> 1.0" or the object syntax with source and version
+func decodeProviderRequirement(attr *hcl.Attribute) (addrs.Provider, *VersionConstraints, hcl.Diagnostics) {
+	addr := addrs.ImpliedProviderForUnqualifiedType(attr.Name)
+
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		vc, vcDiags := decodeVersionConstraints(attr.Expr, fmt.Sprintf("provider %q", attr.Name))
+		return addr, vc, vcDiags
+	}
+
+	var versionExpr hcl.Expression
+	for _, pair := range pairs {
+		key, keyDiags := pair.Key.Value(nil)
+		if keyDiags.HasErrors() || !key.Type().Equals(cty.String) || key.IsNull() {
+			continue
+		}
+
+		switch key.AsString() {
+		case "source":
+			val, valDiags := pair.Value.Value(nil)
+			if valDiags.HasErrors() || !val.Type().Equals(cty.String) || val.IsNull() {
+				return addr, nil, nil
+			}
+			src, err := addrs.ParseProviderSourceString(strings.TrimSpace(val.AsString()))
+			if err != nil {
+				return addr, nil, nil
+			}
+			addr = src
+		case "version":
+			versionExpr = pair.Value
+		}
+	}
+
+	if versionExpr == nil {
+		return addr, nil, nil
+	}
+
+	vc, vcDiags := decodeVersionConstraints(versionExpr, fmt.Sprintf("provider %q", attr.Name))
+	return addr, vc, vcDiags
+}
+
+func decodeVersionConstraints(expr hcl.Expression, subject string) (*VersionConstraints, hcl.Diagnostics) {
+	var rawConstraints string
+	diags := gohcl.DecodeExpression(expr, nil, &rawConstraints)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	constraints, err := version.NewConstraint(rawConstraints)
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid version constraint",
+				Detail: fmt.Sprintf("Version constraints for %s must be a valid constraint string, "+
+					"e.g. \">= 1.0, < 2.0\": %s.", subject, err),
+				Subject: expr.Range().Ptr(),
+			},
+		}
+	}
+
+	return &VersionConstraints{
+		Constraints: constraints,
+		Range:       expr.Range(),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+func TestDecodeRequirements(t *testing.T) {
+	src := `terraform {
+  required_version = ">= 0.13, < 0.15"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+    mycloud = {
+      source = "example.com/mycorp/mycloud"
+    }
+    google = "~> 3.5"
+    invalid = "foo"
+  }
+}
+
+terraform {
+  required_version = "!= 0.13.1"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "versions.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	req, diags := DecodeRequirements(map[string]*hcl.File{
+		"versions.tf": f,
+	})
+
+	expectedDiags := []string{"Invalid version constraint"}
+	givenDiags := make([]string, len(diags))
+	for i, d := range diags {
+		givenDiags[i] = d.Summary
+	}
+	if diff := cmp.Diff(expectedDiags, givenDiags); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+
+	coreVersion := make([]string, len(req.CoreVersion))
+	for i, vc := range req.CoreVersion {
+		coreVersion[i] = vc.Constraints.String()
+	}
+	expectedCoreVersion := []string{">= 0.13, < 0.15", "!= 0.13.1"}
+	if diff := cmp.Diff(expectedCoreVersion, coreVersion); diff != "" {
+		t.Fatalf("core version constraints mismatch: %s", diff)
+	}
+
+	providers := make(map[string][]string, len(req.Providers))
+	for addr, vcs := range req.Providers {
+		for _, vc := range vcs {
+			providers[addr.String()] = append(providers[addr.String()], vc.Constraints.String())
+		}
+	}
+	expectedProviders := map[string][]string{
+		addrs.NewDefaultProvider("aws").String():    {"~> 3.0"},
+		addrs.NewDefaultProvider("google").String(): {"~> 3.5"},
+	}
+	if diff := cmp.Diff(expectedProviders, providers); diff != "" {
+		t.Fatalf("provider constraints mismatch: %s", diff)
+	}
+}
@@ -0,0 +1,171 @@
+package module
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Variable represents a variable block of a module
+type Variable struct {
+	Name        string
+	Description string
+
+	// Type is the type constraint, or cty.DynamicPseudoType
+	// if the constraint is not declared or not valid
+	Type cty.Type
+
+	// Default is the default value, which is only valid
+	// if the variable is not required.
+	Default cty.Value
+
+	// IsRequired indicates that the variable has no default
+	// value and must be set by the caller
+	IsRequired bool
+
+	IsSensitive bool
+
+	DefRange hcl.Range
+}
+
+var variablesSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "variable",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var variableBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "description",
+		},
+		{
+			Name: "type",
+		},
+		{
+			Name: "default",
+		},
+		{
+			Name: "sensitive",
+		},
+	},
+}
+
+// DecodeVariables collects variables declared in the given files,
+// keyed by name, accepting any type constraint syntax
+func DecodeVariables(files map[string]*hcl.File) (map[string]*Variable, hcl.Diagnostics) {
+	return DecodeVariablesForVersion(files, nil)
+}
+
+// DecodeVariablesForVersion collects variables declared in the given files,
+// keyed by name, accepting type constraint syntax supported by the given
+// Terraform version and experiments enabled in the module.
+//
+// Default values are converted to the declared type, or reported
+// in diagnostics if they are not compatible with it.
+func DecodeVariablesForVersion(files map[string]*hcl.File, v *version.Version) (map[string]*Variable, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	variables := make(map[string]*Variable, 0)
+
+	typeOpts := typeexpr.OptionsForVersion(v, DecodeExperiments(files))
+
+	for _, name := range sortedFileNames(files) {
+		f := files[name]
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, contentDiags := f.Body.PartialContent(variablesSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			v, vDiags := decodeVariable(block, typeOpts)
+			diags = append(diags, vDiags...)
+			variables[v.Name] = v
+		}
+	}
+
+	return variables, diags
+}
+
+func decodeVariable(block *hcl.Block, typeOpts typeexpr.Options) (*Variable, hcl.Diagnostics) {
+	v := &Variable{
+		Name:       block.Labels[0],
+		DefRange:   block.DefRange,
+		Type:       cty.DynamicPseudoType,
+		Default:    cty.NilVal,
+		IsRequired: true,
+	}
+
+	content, _, diags := block.Body.PartialContent(variableBlockSchema)
+
+	if attr, ok := content.Attributes["description"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if isKnownString(val) {
+			v.Description = val.AsString()
+		}
+	}
+
+	if attr, ok := content.Attributes["type"]; ok {
+		ty, tyDiags := typeexpr.TypeConstraint(attr.Expr, typeOpts)
+		diags = append(diags, tyDiags...)
+		v.Type = ty
+	}
+
+	if attr, ok := content.Attributes["default"]; ok {
+		v.IsRequired = false
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			val = cty.DynamicVal
+		}
+
+		if v.Type != cty.DynamicPseudoType && val.IsWhollyKnown() {
+			convVal, err := convert.Convert(val, v.Type)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid default value for variable",
+					Detail: fmt.Sprintf("This default value is not compatible with the variable's type constraint: %s.",
+						typeexpr.FormatConversionError(err)),
+					Subject: attr.Expr.Range().Ptr(),
+				})
+				val = cty.DynamicVal
+			} else {
+				val = convVal
+			}
+		}
+		v.Default = val
+	}
+
+	if attr, ok := content.Attributes["sensitive"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && isKnownBool(val) {
+			v.IsSensitive = val.True()
+		}
+	}
+
+	return v, diags
+}
+
+func isKnownString(val cty.Value) bool {
+	return val.Type() == cty.String && val.IsKnown() && !val.IsNull()
+}
+
+func sortedFileNames(files map[string]*hcl.File) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
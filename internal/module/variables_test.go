@@ -0,0 +1,173 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDecodeVariables(t *testing.T) {
+	src := `variable "name" {
+  type        = string
+  description = "Name of the instance"
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {
+    env = "dev"
+  }
+}
+
+variable "password" {
+  default   = null
+  sensitive = true
+}
+
+variable "invalid_type" {
+  type = foo(string)
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "variables.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	variables, _ := DecodeVariables(map[string]*hcl.File{
+		"variables.tf": f,
+	})
+
+	expectedVariables := map[string]*Variable{
+		"name": {
+			Name:        "name",
+			Description: "Name of the instance",
+			Type:        cty.String,
+			Default:     cty.NilVal,
+			IsRequired:  true,
+		},
+		"tags": {
+			Name: "tags",
+			Type: cty.Map(cty.String),
+			Default: cty.MapVal(map[string]cty.Value{
+				"env": cty.StringVal("dev"),
+			}),
+		},
+		"password": {
+			Name:        "password",
+			Type:        cty.DynamicPseudoType,
+			Default:     cty.NullVal(cty.DynamicPseudoType),
+			IsSensitive: true,
+		},
+		"invalid_type": {
+			Name:       "invalid_type",
+			Type:       cty.DynamicPseudoType,
+			Default:    cty.NilVal,
+			IsRequired: true,
+		},
+	}
+	if diff := cmp.Diff(expectedVariables, variables, ctydebug.CmpOptions, cmpopts.IgnoreFields(Variable{}, "DefRange")); diff != "" {
+		t.Fatalf("variables mismatch: %s", diff)
+	}
+}
+
+func TestDecodeVariablesForVersion_optionalAttrs(t *testing.T) {
+	src := `terraform {
+  experiments = [module_variable_optional_attrs]
+}
+
+variable "server" {
+  type = object({
+    name = string
+    port = optional(number)
+  })
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "variables.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	files := map[string]*hcl.File{
+		"variables.tf": f,
+	}
+
+	variables, diags := DecodeVariablesForVersion(files, version.Must(version.NewVersion("0.14.0")))
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedType := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name": cty.String,
+		"port": cty.Number,
+	}, []string{"port"})
+	if !variables["server"].Type.Equals(expectedType) {
+		t.Fatalf("type mismatch.\nexpected: %#v\ngiven: %#v",
+			expectedType, variables["server"].Type)
+	}
+
+	_, diags = DecodeVariablesForVersion(files, version.Must(version.NewVersion("0.13.0")))
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic for 0.13, %d given: %s", len(diags), diags)
+	}
+}
+
+func TestDecodeVariables_invalidDefault(t *testing.T) {
+	src := `variable "ports" {
+  type    = list(number)
+  default = ["80", "http"]
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "variables.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	variables, diags := DecodeVariables(map[string]*hcl.File{
+		"variables.tf": f,
+	})
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for variable",
+			Detail:   "This default value is not compatible with the variable's type constraint: [1]: a number is required.",
+			Subject: &hcl.Range{
+				Filename: "variables.tf",
+				Start:    hcl.Pos{Line: 3, Column: 13, Byte: 56},
+				End:      hcl.Pos{Line: 3, Column: 27, Byte: 70},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+	if !variables["ports"].Default.RawEquals(cty.DynamicVal) {
+		t.Fatalf("expected invalid default to be unknown, given: %#v", variables["ports"].Default)
+	}
+}
+
+func TestDecodeExperiments(t *testing.T) {
+	src := `terraform {
+  experiments = [module_variable_optional_attrs, "invalid"]
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	experiments := DecodeExperiments(map[string]*hcl.File{
+		"main.tf": f,
+	})
+	expected := map[string]bool{
+		"module_variable_optional_attrs": true,
+	}
+	if diff := cmp.Diff(expected, experiments); diff != "" {
+		t.Fatalf("experiments mismatch: %s", diff)
+	}
+}
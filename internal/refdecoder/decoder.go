@@ -2,31 +2,62 @@ package refdecoder
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/zclconf/go-cty/cty"
 )
 
+// DecodeProviderReferences walks the given files (native or JSON syntax)
+// and collects references to providers from the required_providers
+// and provider blocks, as well as resources and data sources.
 func DecodeProviderReferences(m map[string]*hcl.File) (addrs.ProviderReferences, hcl.Diagnostics) {
+	return DecodeProviderReferencesWithInherited(m, nil)
+}
+
+// DecodeProviderReferencesWithInherited is like DecodeProviderReferences
+// but resolves any local names which are not declared in required_providers
+// using the inherited map (local name to provider address) of a child module,
+// as passed by the parent module, before falling back to implied providers.
+func DecodeProviderReferencesWithInherited(m map[string]*hcl.File, inherited map[string]addrs.Provider) (addrs.ProviderReferences, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
-	mod := tfconfig.NewModule("")
-	for _, f := range m {
-		fDiags := tfconfig.LoadModuleFromFile(f, mod)
-		diags = append(diags, fDiags...)
+	mod := &module{
+		requiredProviders: make(map[string]*requiredProvider, 0),
+	}
+
+	// Decode files in a stable order to produce stable diagnostics
+	fileNames := make([]string, 0, len(m))
+	for name := range m {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	for _, name := range fileNames {
+		f := m[name]
+		if f == nil || f.Body == nil {
+			continue
+		}
+		diags = append(diags, mod.loadBody(f.Body)...)
 	}
 
 	refs := make(addrs.ProviderReferences, 0)
 
-	for name, req := range mod.RequiredProviders {
-		var src addrs.Provider
+	impliedProvider := func(localName string) addrs.Provider {
+		if src, ok := inherited[localName]; ok {
+			return src
+		}
+		return addrs.ImpliedProviderForUnqualifiedType(localName)
+	}
+
+	for _, name := range mod.requiredProviderNames() {
+		req := mod.requiredProviders[name]
 
+		var src addrs.Provider
 		if req.Source == "" {
-			if name == "" {
-				continue
-			}
-			src = addrs.ImpliedProviderForUnqualifiedType(name)
+			src = impliedProvider(name)
 		} else {
 			var err error
 			src, err = addrs.ParseProviderSourceString(req.Source)
@@ -35,6 +66,7 @@ func DecodeProviderReferences(m map[string]*hcl.File) (addrs.ProviderReferences,
 					Severity: hcl.DiagError,
 					Summary:  fmt.Sprintf("Unable to parse provider source for %q", name),
 					Detail:   fmt.Sprintf("%q provider source (%q) is not a valid source string", name, req.Source),
+					Subject:  req.SourceRange.Ptr(),
 				})
 				continue
 			}
@@ -43,39 +75,259 @@ func DecodeProviderReferences(m map[string]*hcl.File) (addrs.ProviderReferences,
 		refs[addrs.LocalProviderConfig{
 			LocalName: name,
 		}] = src
-	}
 
-	for _, cfg := range mod.ProviderConfigs {
-		src := refs[addrs.LocalProviderConfig{
-			LocalName: cfg.Name,
-		}]
-		if cfg.Alias != "" {
+		for _, alias := range req.ConfigurationAliases {
 			refs[addrs.LocalProviderConfig{
-				LocalName: cfg.Name,
-				Alias:     cfg.Alias,
+				LocalName: name,
+				Alias:     alias,
 			}] = src
 		}
 	}
 
-	for _, resource := range mod.ManagedResources {
-		providerName := resource.Provider.Name
+	for _, cfg := range mod.providerConfigs {
 		localRef := addrs.LocalProviderConfig{
-			LocalName: providerName,
+			LocalName: cfg.LocalName,
 		}
-		if _, exists := refs[localRef]; !exists && providerName != "" {
-			refs[localRef] = addrs.ImpliedProviderForUnqualifiedType(providerName)
+		src, exists := refs[localRef]
+		if !exists {
+			if _, declared := mod.requiredProviders[cfg.LocalName]; declared {
+				// declared with invalid source
+				continue
+			}
+			src = impliedProvider(cfg.LocalName)
+			refs[localRef] = src
+		}
+		if cfg.Alias != "" {
+			refs[cfg] = src
 		}
 	}
 
-	for _, dataSource := range mod.DataResources {
-		providerName := dataSource.Provider.Name
+	for _, providerName := range mod.resourceProviderNames {
 		localRef := addrs.LocalProviderConfig{
 			LocalName: providerName,
 		}
 		if _, exists := refs[localRef]; !exists && providerName != "" {
-			refs[localRef] = addrs.ImpliedProviderForUnqualifiedType(providerName)
+			if _, declared := mod.requiredProviders[providerName]; declared {
+				// declared with invalid source
+				continue
+			}
+			refs[localRef] = impliedProvider(providerName)
 		}
 	}
 
 	return refs, diags
 }
+
+type module struct {
+	requiredProviders     map[string]*requiredProvider
+	providerConfigs       []addrs.LocalProviderConfig
+	resourceProviderNames []string
+}
+
+type requiredProvider struct {
+	Source               string
+	SourceRange          hcl.Range
+	ConfigurationAliases []string
+}
+
+func (mod *module) requiredProviderNames() []string {
+	names := make([]string, 0, len(mod.requiredProviders))
+	for name := range mod.requiredProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var rootSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "terraform",
+		},
+		{
+			Type:       "provider",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "resource",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+		},
+	},
+}
+
+var terraformBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "required_providers",
+		},
+	},
+}
+
+var providerBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "alias",
+		},
+	},
+}
+
+var resourceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "provider",
+		},
+	},
+}
+
+func (mod *module) loadBody(body hcl.Body) hcl.Diagnostics {
+	content, _, diags := body.PartialContent(rootSchema)
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "terraform":
+			diags = append(diags, mod.loadTerraformBlock(block)...)
+		case "provider":
+			diags = append(diags, mod.loadProviderBlock(block)...)
+		case "resource", "data":
+			diags = append(diags, mod.loadResourceBlock(block)...)
+		}
+	}
+
+	return diags
+}
+
+func (mod *module) loadTerraformBlock(block *hcl.Block) hcl.Diagnostics {
+	content, _, diags := block.Body.PartialContent(terraformBlockSchema)
+
+	for _, rpBlock := range content.Blocks {
+		attrs, attrDiags := rpBlock.Body.JustAttributes()
+		diags = append(diags, attrDiags...)
+
+		for name, attr := range attrs {
+			req, reqDiags := decodeRequiredProvider(attr)
+			diags = append(diags, reqDiags...)
+
+			if existing, ok := mod.requiredProviders[name]; ok {
+				// The last source wins, aliases accumulate
+				// which reflects merging of multiple blocks
+				if req.Source != "" {
+					existing.Source = req.Source
+					existing.SourceRange = req.SourceRange
+				}
+				existing.ConfigurationAliases = append(existing.ConfigurationAliases, req.ConfigurationAliases...)
+				continue
+			}
+			mod.requiredProviders[name] = req
+		}
+	}
+
+	return diags
+}
+
+func decodeRequiredProvider(attr *hcl.Attribute) (*requiredProvider, hcl.Diagnostics) {
+	req := &requiredProvider{}
+
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		// Legacy (0.12) syntax, e.g. aws = "~> 1.0"
+		// which only carries the version constraint
+		return req, nil
+	}
+
+	for _, pair := range pairs {
+		key, keyDiags := pair.Key.Value(nil)
+		if keyDiags.HasErrors() || !key.Type().Equals(cty.String) || key.IsNull() {
+			continue
+		}
+
+		switch key.AsString() {
+		case "source":
+			val, valDiags := pair.Value.Value(nil)
+			if valDiags.HasErrors() || !val.Type().Equals(cty.String) || val.IsNull() {
+				return req, hcl.Diagnostics{
+					{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid provider source",
+						Detail:   fmt.Sprintf("Provider source for %q must be a string", attr.Name),
+						Subject:  pair.Value.Range().Ptr(),
+					},
+				}
+			}
+			req.Source = strings.TrimSpace(val.AsString())
+			req.SourceRange = pair.Value.Range()
+		case "configuration_aliases":
+			exprs, exprDiags := hcl.ExprList(pair.Value)
+			if exprDiags.HasErrors() {
+				diags = append(diags, exprDiags...)
+				continue
+			}
+			for _, expr := range exprs {
+				traversal, travDiags := hcl.AbsTraversalForExpr(expr)
+				if travDiags.HasErrors() {
+					diags = append(diags, travDiags...)
+					continue
+				}
+				ref, err := addrs.ParseProviderConfigCompact(traversal)
+				if err != nil || ref.Alias == "" {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid configuration alias",
+						Detail: fmt.Sprintf("Configuration aliases for %q must be references "+
+							"to aliased provider configurations, e.g. %s.alias", attr.Name, attr.Name),
+						Subject: expr.Range().Ptr(),
+					})
+					continue
+				}
+				req.ConfigurationAliases = append(req.ConfigurationAliases, ref.Alias)
+			}
+		}
+	}
+
+	return req, diags
+}
+
+func (mod *module) loadProviderBlock(block *hcl.Block) hcl.Diagnostics {
+	content, _, diags := block.Body.PartialContent(providerBlockSchema)
+
+	cfg := addrs.LocalProviderConfig{
+		LocalName: block.Labels[0],
+	}
+
+	if attr, ok := content.Attributes["alias"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && val.Type().Equals(cty.String) && !val.IsNull() {
+			cfg.Alias = val.AsString()
+		}
+	}
+
+	mod.providerConfigs = append(mod.providerConfigs, cfg)
+
+	return diags
+}
+
+func (mod *module) loadResourceBlock(block *hcl.Block) hcl.Diagnostics {
+	content, _, diags := block.Body.PartialContent(resourceBlockSchema)
+
+	var providerAttr hcl.Traversal
+	if attr, ok := content.Attributes["provider"]; ok {
+		traversal, travDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, travDiags...)
+		if !travDiags.HasErrors() {
+			providerAttr = traversal
+		}
+	}
+
+	providerName := addrs.ImpliedProviderLocalName(block.Labels[0])
+	if cfg, err := addrs.ResourceProviderConfig(block.Labels[0], providerAttr); err == nil {
+		providerName = cfg.LocalName
+	}
+
+	mod.resourceProviderNames = append(mod.resourceProviderNames, providerName)
+
+	return diags
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/hashicorp/terraform-schema/internal/addrs"
 )
 
@@ -114,6 +115,87 @@ data "mycloud_instance" "foo" {
 				},
 			},
 		},
+		{
+			"configuration aliases",
+			`
+terraform {
+  required_providers {
+    mycloud = {
+      source                = "mycorp/mycloud"
+      configuration_aliases = [ mycloud.west, mycloud.east ]
+    }
+  }
+}
+`,
+			addrs.ProviderReferences{
+				addrs.LocalProviderConfig{
+					LocalName: "mycloud",
+				}: addrs.Provider{
+					Hostname:  addrs.DefaultRegistryHost,
+					Namespace: "mycorp",
+					Type:      "mycloud",
+				},
+				addrs.LocalProviderConfig{
+					LocalName: "mycloud",
+					Alias:     "west",
+				}: addrs.Provider{
+					Hostname:  addrs.DefaultRegistryHost,
+					Namespace: "mycorp",
+					Type:      "mycloud",
+				},
+				addrs.LocalProviderConfig{
+					LocalName: "mycloud",
+					Alias:     "east",
+				}: addrs.Provider{
+					Hostname:  addrs.DefaultRegistryHost,
+					Namespace: "mycorp",
+					Type:      "mycloud",
+				},
+			},
+		},
+		{
+			"legacy required_providers",
+			`
+terraform {
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+`,
+			addrs.ProviderReferences{
+				addrs.LocalProviderConfig{
+					LocalName: "aws",
+				}: addrs.Provider{
+					Hostname:  addrs.DefaultRegistryHost,
+					Namespace: "hashicorp",
+					Type:      "aws",
+				},
+			},
+		},
+		{
+			"resource with provider",
+			`
+terraform {
+  required_providers {
+    gb = {
+      source = "hashicorp/google-beta"
+    }
+  }
+}
+resource "google_compute_instance" "foo" {
+  provider = gb.europe
+}
+`,
+			addrs.ProviderReferences{
+				addrs.LocalProviderConfig{
+					LocalName: "gb",
+				}: addrs.Provider{
+					Hostname:  addrs.DefaultRegistryHost,
+					Namespace: "hashicorp",
+					Type:      "google-beta",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -128,9 +210,173 @@ data "mycloud_instance" "foo" {
 			}
 
 			refs, diags := DecodeProviderReferences(files)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
 			if diff := cmp.Diff(tc.expectedRefs, refs); diff != "" {
 				t.Fatalf("unexpected provider references: %s", diff)
 			}
 		})
 	}
 }
+
+func TestDecodeProviderReferences_json(t *testing.T) {
+	src := `{
+  "terraform": {
+    "required_providers": {
+      "mycloud": {
+        "source": "mycorp/mycloud",
+        "configuration_aliases": ["mycloud.west"]
+      }
+    }
+  },
+  "provider": {
+    "aws": {
+      "alias": "east"
+    }
+  },
+  "data": {
+    "random_string": {
+      "foo": {}
+    }
+  }
+}`
+	f, diags := hcljson.Parse([]byte(src), "test.tf.json")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	refs, diags := DecodeProviderReferences(map[string]*hcl.File{
+		"test.tf.json": f,
+	})
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedRefs := addrs.ProviderReferences{
+		addrs.LocalProviderConfig{
+			LocalName: "mycloud",
+		}: addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "mycloud"),
+		addrs.LocalProviderConfig{
+			LocalName: "mycloud",
+			Alias:     "west",
+		}: addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "mycloud"),
+		addrs.LocalProviderConfig{
+			LocalName: "aws",
+		}: addrs.NewDefaultProvider("aws"),
+		addrs.LocalProviderConfig{
+			LocalName: "aws",
+			Alias:     "east",
+		}: addrs.NewDefaultProvider("aws"),
+		addrs.LocalProviderConfig{
+			LocalName: "random",
+		}: addrs.NewDefaultProvider("random"),
+	}
+	if diff := cmp.Diff(expectedRefs, refs); diff != "" {
+		t.Fatalf("unexpected provider references: %s", diff)
+	}
+}
+
+func TestDecodeProviderReferences_invalidSource(t *testing.T) {
+	src := `terraform {
+  required_providers {
+    mycloud = {
+      source = "mycorp/my_cloud/foo/bar"
+    }
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	refs, diags := DecodeProviderReferences(map[string]*hcl.File{
+		"test.tf": f,
+	})
+
+	expectedRefs := addrs.ProviderReferences{
+		addrs.LocalProviderConfig{
+			LocalName: "aws",
+		}: addrs.NewDefaultProvider("aws"),
+	}
+	if diff := cmp.Diff(expectedRefs, refs); diff != "" {
+		t.Fatalf("unexpected provider references: %s", diff)
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  `Unable to parse provider source for "mycloud"`,
+			Detail:   `"mycloud" provider source ("mycorp/my_cloud/foo/bar") is not a valid source string`,
+			Subject: &hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 4, Column: 16, Byte: 66},
+				End:      hcl.Pos{Line: 4, Column: 41, Byte: 91},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestDecodeProviderReferencesWithInherited(t *testing.T) {
+	src := `terraform {
+  required_providers {
+    random = {
+      source = "mycorp/random"
+    }
+  }
+}
+
+provider "aws" {
+  alias = "west"
+}
+
+resource "google_compute_instance" "foo" {
+}
+
+resource "random_id" "foo" {
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	inherited := map[string]addrs.Provider{
+		"aws":    addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "aws"),
+		"random": addrs.NewProvider(addrs.DefaultRegistryHost, "othercorp", "random"),
+	}
+
+	refs, diags := DecodeProviderReferencesWithInherited(map[string]*hcl.File{
+		"test.tf": f,
+	}, inherited)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedRefs := addrs.ProviderReferences{
+		addrs.LocalProviderConfig{
+			LocalName: "aws",
+		}: addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "aws"),
+		addrs.LocalProviderConfig{
+			LocalName: "aws",
+			Alias:     "west",
+		}: addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "aws"),
+		addrs.LocalProviderConfig{
+			LocalName: "google",
+		}: addrs.NewDefaultProvider("google"),
+		// explicit declaration takes precedence
+		addrs.LocalProviderConfig{
+			LocalName: "random",
+		}: addrs.NewProvider(addrs.DefaultRegistryHost, "mycorp", "random"),
+	}
+	if diff := cmp.Diff(expectedRefs, refs); diff != "" {
+		t.Fatalf("unexpected provider references: %s", diff)
+	}
+}
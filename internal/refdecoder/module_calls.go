@@ -0,0 +1,140 @@
+package refdecoder
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleCall represents a module block
+type ModuleCall struct {
+	Name   string
+	Source string
+
+	// Providers maps provider configurations of the child module
+	// to configurations in the calling (parent) module
+	Providers map[addrs.LocalProviderConfig]addrs.LocalProviderConfig
+
+	// HasProviders indicates whether the providers argument was set,
+	// which disables implicit inheritance of default provider configurations
+	HasProviders bool
+
+	HasCount   bool
+	HasForEach bool
+}
+
+var moduleCallsSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var moduleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "source",
+		},
+		{
+			Name: "providers",
+		},
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+	},
+}
+
+// DecodeModuleCalls walks the given files (native or JSON syntax)
+// and collects module calls, sorted by name
+func DecodeModuleCalls(m map[string]*hcl.File) ([]*ModuleCall, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	calls := make([]*ModuleCall, 0)
+
+	for _, f := range m {
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, contentDiags := f.Body.PartialContent(moduleCallsSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			call, callDiags := decodeModuleCall(block)
+			diags = append(diags, callDiags...)
+			calls = append(calls, call)
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Name < calls[j].Name
+	})
+
+	return calls, diags
+}
+
+func decodeModuleCall(block *hcl.Block) (*ModuleCall, hcl.Diagnostics) {
+	call := &ModuleCall{
+		Name:      block.Labels[0],
+		Providers: make(map[addrs.LocalProviderConfig]addrs.LocalProviderConfig, 0),
+	}
+
+	content, _, diags := block.Body.PartialContent(moduleBlockSchema)
+
+	if attr, ok := content.Attributes["source"]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && val.Type().Equals(cty.String) && !val.IsNull() {
+			call.Source = val.AsString()
+		}
+	}
+
+	_, call.HasCount = content.Attributes["count"]
+	_, call.HasForEach = content.Attributes["for_each"]
+
+	if attr, ok := content.Attributes["providers"]; ok {
+		call.HasProviders = true
+
+		pairs, pairDiags := hcl.ExprMap(attr.Expr)
+		diags = append(diags, pairDiags...)
+
+		for _, pair := range pairs {
+			childRef, childDiags := decodeProviderConfigExpr(pair.Key)
+			diags = append(diags, childDiags...)
+			parentRef, parentDiags := decodeProviderConfigExpr(pair.Value)
+			diags = append(diags, parentDiags...)
+			if childDiags.HasErrors() || parentDiags.HasErrors() {
+				continue
+			}
+			call.Providers[childRef] = parentRef
+		}
+	}
+
+	return call, diags
+}
+
+func decodeProviderConfigExpr(expr hcl.Expression) (addrs.LocalProviderConfig, hcl.Diagnostics) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return addrs.LocalProviderConfig{}, diags
+	}
+
+	ref, err := addrs.ParseProviderConfigCompact(traversal)
+	if err != nil {
+		return ref, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration reference",
+				Detail:   err.Error(),
+				Subject:  expr.Range().Ptr(),
+			},
+		}
+	}
+
+	return ref, nil
+}
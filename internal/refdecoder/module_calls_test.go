@@ -0,0 +1,81 @@
+package refdecoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+func TestDecodeModuleCalls(t *testing.T) {
+	src := `module "network" {
+  source = "./modules/network"
+  providers = {
+    aws      = aws.west
+    aws.peer = aws
+  }
+}
+
+module "consul" {
+  source = "hashicorp/consul/aws"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	jsonSrc := `{
+  "module": {
+    "storage": {
+      "source": "../storage",
+      "providers": {
+        "google": "google.eu"
+      }
+    }
+  }
+}`
+	jsonFile, diags := hcljson.Parse([]byte(jsonSrc), "test.tf.json")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	calls, diags := DecodeModuleCalls(map[string]*hcl.File{
+		"test.tf":      f,
+		"test.tf.json": jsonFile,
+	})
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedCalls := []*ModuleCall{
+		{
+			Name:      "consul",
+			Source:    "hashicorp/consul/aws",
+			Providers: map[addrs.LocalProviderConfig]addrs.LocalProviderConfig{},
+		},
+		{
+			Name:   "network",
+			Source: "./modules/network",
+			Providers: map[addrs.LocalProviderConfig]addrs.LocalProviderConfig{
+				{LocalName: "aws"}:                {LocalName: "aws", Alias: "west"},
+				{LocalName: "aws", Alias: "peer"}: {LocalName: "aws"},
+			},
+			HasProviders: true,
+		},
+		{
+			Name:   "storage",
+			Source: "../storage",
+			Providers: map[addrs.LocalProviderConfig]addrs.LocalProviderConfig{
+				{LocalName: "google"}: {LocalName: "google", Alias: "eu"},
+			},
+			HasProviders: true,
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Fatalf("unexpected module calls: %s", diff)
+	}
+}
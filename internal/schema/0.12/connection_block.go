@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var connectionBlock = &schema.BlockSchema{
+	Description: lang.Markdown("Connection block describing how the provisioner connects to the given instance"),
+	MaxItems:    1,
+	Body: &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"type": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Connection type to use - `ssh` (default) or `winrm`"),
+				IsDepKey:    true,
+			},
+			"user": {
+				ValueType:  cty.String,
+				IsOptional: true,
+				Description: lang.Markdown("User to use for the connection, defaults to `root` for `ssh` " +
+					"and `Administrator` for `winrm`"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password to use for the connection"),
+			},
+			"host": {
+				ValueType:   cty.String,
+				IsRequired:  true,
+				Description: lang.Markdown("Address of the resource to connect to, e.g. `self.public_ip`"),
+			},
+			"port": {
+				ValueType:   cty.Number,
+				IsOptional:  true,
+				Description: lang.Markdown("Port to connect to, defaults to `22` for `ssh` and `5985` for `winrm`"),
+			},
+			"timeout": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Timeout to wait for the connection to become available, e.g. `30s`, defaults to `5m`"),
+			},
+			"script_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path used to copy scripts meant for remote execution"),
+			},
+		},
+	},
+	DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+		// type defaults to ssh, so the ssh body also applies
+		// to connection blocks which do not declare any type
+		schema.NewSchemaKey(schema.DependencyKeys{}): sshConnectionBody,
+		connectionTypeKey("ssh"):                     sshConnectionBody,
+		connectionTypeKey("winrm"):                   winrmConnectionBody,
+	},
+}
+
+func connectionTypeKey(connType string) schema.SchemaKey {
+	return schema.NewSchemaKey(schema.DependencyKeys{
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "type",
+				Expr: schema.ExpressionValue{
+					Static: cty.StringVal(connType),
+				},
+			},
+		},
+	})
+}
+
+var sshConnectionBody = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"private_key": {
+			ValueType:  cty.String,
+			IsOptional: true,
+			Description: lang.Markdown("Contents of an SSH key to use for the connection, e.g. `file(\"~/.ssh/id_rsa\")`. " +
+				"Takes precedence over `password`"),
+		},
+		"certificate": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Contents of a signed CA certificate, to be used in conjunction with `private_key`"),
+		},
+		"agent": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use `ssh-agent` for authentication, defaults to `true` on non-Windows systems"),
+		},
+		"agent_identity": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Preferred identity from the SSH agent for authentication"),
+		},
+		"host_key": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Public key from the remote host or the signing CA, used to verify the connection"),
+		},
+		"bastion_host": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Address of a bastion host to connect through, enables the bastion host connection"),
+		},
+		"bastion_host_key": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Public key from the bastion host or the signing CA, used to verify the connection"),
+		},
+		"bastion_port": {
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Port to connect to on the bastion host, defaults to `port`"),
+		},
+		"bastion_user": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("User to use for the bastion host connection, defaults to `user`"),
+		},
+		"bastion_password": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Password to use for the bastion host connection, defaults to `password`"),
+		},
+		"bastion_private_key": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Contents of an SSH key to use for the bastion host connection, defaults to `private_key`"),
+		},
+		"bastion_certificate": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Contents of a signed CA certificate, to be used in conjunction with `bastion_private_key`"),
+		},
+	},
+}
+
+var winrmConnectionBody = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"https": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to connect using HTTPS instead of HTTP"),
+		},
+		"insecure": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to skip validation of the HTTPS certificate chain"),
+		},
+		"use_ntlm": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use NTLM authentication instead of basic authentication"),
+		},
+		"cacert": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("CA certificate to validate against"),
+		},
+	},
+}
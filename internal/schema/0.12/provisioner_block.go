@@ -0,0 +1,630 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func provisionerBlock(v *version.Version) *schema.BlockSchema {
+	bs := &schema.BlockSchema{
+		Description: lang.Markdown("Provisioner to model specific actions on the local machine or on a remote machine " +
+			"in order to prepare servers or other infrastructure objects for service"),
+		Labels: []*schema.LabelSchema{
+			{
+				Name:        "type",
+				Description: lang.PlainText("Type of provisioner to use, e.g. `remote-exec` or `file`"),
+				IsDepKey:    true,
+			},
+		},
+		Body: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"when": {
+					ValueType:  cty.DynamicPseudoType,
+					IsOptional: true,
+					Description: lang.Markdown("When to run the provisioner - `create` or `destroy`, defaults to `create` " +
+						"(i.e. after creation of the resource)"),
+				},
+				"on_failure": {
+					IsOptional: true,
+					ValueType:  cty.DynamicPseudoType,
+					Description: lang.Markdown("What to do when the provisioner run fails to finish - `fail` (default), " +
+						"or `continue` (ignore the error)"),
+				},
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"connection": connectionBlock,
+			},
+		},
+		DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+			provisionerKey("local-exec"):      localExecProvisioner,
+			provisionerKey("remote-exec"):     remoteExecProvisioner,
+			provisionerKey("file"):            fileProvisioner,
+			provisionerKey("chef"):            chefProvisioner,
+			provisionerKey("habitat"):         habitatProvisioner,
+			provisionerKey("salt-masterless"): saltMasterlessProvisioner,
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_12_2) {
+		bs.DependentBody[provisionerKey("puppet")] = puppetProvisioner
+	}
+
+	return bs
+}
+
+func provisionerKey(name string) schema.SchemaKey {
+	return schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: name},
+		},
+	})
+}
+
+var localExecProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Invokes a local executable after a resource is created"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"command": {
+			ValueType:  cty.String,
+			IsRequired: true,
+			Description: lang.Markdown("Command to execute, evaluated in a shell. " +
+				"It can use environment variables for variable data, e.g. `echo $FOO >> file.txt`"),
+		},
+		"working_dir": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Working directory where `command` will be executed"),
+		},
+		"interpreter": {
+			ValueType:  cty.List(cty.String),
+			IsOptional: true,
+			Description: lang.Markdown("Interpreter and its arguments used to execute `command`, " +
+				"e.g. `[\"/bin/bash\", \"-c\"]`"),
+		},
+		"environment": {
+			ValueType:   cty.Map(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Map of environment variables to pass to `command`"),
+		},
+	},
+}
+
+var remoteExecProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Invokes a script on a remote resource after it is created"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"inline": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of command strings executed in the order they are provided"),
+		},
+		"script": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Path (relative or absolute) to a local script that will be copied to the remote resource and then executed"),
+		},
+		"scripts": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of paths (relative or absolute) to local scripts that will be copied to the remote resource and then executed in order"),
+		},
+	},
+}
+
+var fileProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Copies files or directories from the machine executing Terraform to the newly created resource"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"source": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Source file or folder, relative to the current working directory or absolute. Conflicts with `content`"),
+		},
+		"content": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Content to copy to the destination file. Conflicts with `source`"),
+		},
+		"destination": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("Absolute path on the remote machine to copy the file or folder to"),
+		},
+	},
+}
+
+var chefProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Installs, configures and runs the Chef Client on a remote resource"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"attributes_json": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Raw JSON string with initial node attributes for the new node"),
+		},
+		"channel": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Chef Client release channel to install from, defaults to `stable`"),
+		},
+		"client_options": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of optional Chef Client configuration options"),
+		},
+		"disable_reporting": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to disable Chef Client reporting to the Chef Server"),
+		},
+		"environment": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Chef environment the new node will be joining, defaults to `_default`"),
+		},
+		"fetch_chef_certificates": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to download SSL certificates from the Chef Server"),
+		},
+		"log_to_file": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to write the output of the initial Chef Client run to a local log file"),
+		},
+		"use_policyfile": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use Policyfiles instead of a run list and environment"),
+		},
+		"policy_group": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of a policy group that exists on the Chef Server"),
+		},
+		"policy_name": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of a policy, as identified by the `name` setting in a Policyfile"),
+		},
+		"http_proxy": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Proxy server for Chef Client HTTP connections"),
+		},
+		"https_proxy": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Proxy server for Chef Client HTTPS connections"),
+		},
+		"max_retries": {
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Number of times to retry the Chef Client run"),
+		},
+		"no_proxy": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of URLs which should not use the proxy servers"),
+		},
+		"named_run_list": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of an alternate run list to invoke during the initial Chef Client run"),
+		},
+		"node_name": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("Name of the new node within Chef"),
+		},
+		"ohai_hints": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of paths to Ohai hint files to upload to the remote machine"),
+		},
+		"os_type": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("OS type of the node, `linux` or `windows`"),
+		},
+		"prevent_sudo": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to prevent the use of `sudo` while installing, configuring and running the Chef Client"),
+		},
+		"recreate_client": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to remove an existing node and client with the same name from the Chef Server first"),
+		},
+		"retry_on_exit_code": {
+			ValueType:   cty.List(cty.Number),
+			IsOptional:  true,
+			Description: lang.Markdown("List of exit codes of the Chef Client run which trigger a retry"),
+		},
+		"run_list": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List with recipes and/or roles to apply during the initial Chef Client run"),
+		},
+		"secret_key": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Contents of the secret key used by the client to decrypt encrypted data bags"),
+		},
+		"server_url": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("URL to the Chef server, including the organization, e.g. `https://chef.local/organizations/org1`"),
+		},
+		"skip_install": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to skip the installation of the Chef Client on the remote machine"),
+		},
+		"skip_register": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to skip the registration of Chef Client on the remote machine"),
+		},
+		"ssl_verify_mode": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Chef Client SSL verification mode, `:verify_none` or `:verify_peer`"),
+		},
+		"user_name": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("Name of an existing Chef user used for registering the new Chef Client and creating the node"),
+		},
+		"user_key": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("Contents of the user key used to authenticate with the Chef Server"),
+		},
+		"vault_json": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Raw JSON string with Chef Vaults and Items to which the new node should have access"),
+		},
+		"version": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Chef Client version to install on the remote machine"),
+		},
+		"wait_for_retry": {
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Number of seconds to wait before retrying the Chef Client run"),
+		},
+	},
+}
+
+var habitatProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Installs and configures the Habitat supervisor and starts services on a remote resource"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"version": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Habitat version to install on the remote machine, defaults to latest"),
+		},
+		"license": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to accept the Habitat end user license agreement, `accept` or `accept-no-persist`"),
+		},
+		"auto_update": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether the supervisor should auto-update itself"),
+		},
+		"http_disable": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to disable the supervisor HTTP listener"),
+		},
+		"peers": {
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of IP addresses or hostnames of peers for the supervisor to join"),
+		},
+		"service_type": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Method used to run the supervisor, `systemd` (default) or `unmanaged`"),
+		},
+		"service_name": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of the supervisor service when using the `systemd` service type"),
+		},
+		"use_sudo": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use `sudo` when executing the provisioner, defaults to `true`"),
+		},
+		"permanent_peer": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to mark this supervisor as a permanent peer"),
+		},
+		"listen_ctl": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Listen address for the supervisor control gateway"),
+		},
+		"listen_gossip": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Listen address for the supervisor gossip protocol"),
+		},
+		"listen_http": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Listen address for the supervisor HTTP gateway"),
+		},
+		"ring_key": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of the ring key for encrypting gossip ring communication"),
+		},
+		"ring_key_content": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Contents of the ring key for encrypting gossip ring communication"),
+		},
+		"ctl_secret": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Secret key used to authenticate control gateway requests"),
+		},
+		"url": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("URL of a Builder service to download packages and receive updates from"),
+		},
+		"channel": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Release channel in the Builder service to use, defaults to `stable`"),
+		},
+		"events": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Name of the service group running a Habitat EventSrv to forward supervisor and service events to"),
+		},
+		"organization": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Organization the supervisor and its services are part of"),
+		},
+		"gateway_auth_token": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Authentication token for the supervisor HTTP gateway"),
+		},
+		"builder_auth_token": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Builder authentication token to install packages from private origins"),
+		},
+	},
+	Blocks: map[string]*schema.BlockSchema{
+		"service": {
+			Description: lang.Markdown("Habitat service to load and start"),
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"name": {
+						ValueType:   cty.String,
+						IsRequired:  true,
+						Description: lang.Markdown("Name of the Habitat package to start, e.g. `core/redis`"),
+					},
+					"binds": {
+						ValueType:   cty.List(cty.String),
+						IsOptional:  true,
+						Description: lang.Markdown("List of service group names to bind to, e.g. `[\"backend:nginx.default\"]`"),
+					},
+					"topology": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Topology of the service, `standalone` or `leader`"),
+					},
+					"strategy": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Update strategy of the service, `none`, `rolling` or `at-once`"),
+					},
+					"user_toml": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Contents of a custom `user.toml` for the service"),
+					},
+					"channel": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Release channel in the Builder service to use"),
+					},
+					"group": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Service group name, defaults to `default`"),
+					},
+					"url": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("URL of a Builder service to download the package from"),
+					},
+					"application": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Application name"),
+					},
+					"environment": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Environment name"),
+					},
+					"service_key": {
+						ValueType:   cty.String,
+						IsOptional:  true,
+						Description: lang.Markdown("Key content of a service private key, if using service group encryption"),
+					},
+				},
+				Blocks: map[string]*schema.BlockSchema{
+					"bind": {
+						Description: lang.Markdown("Alternative way of declaring a bind to another service group"),
+						Body: &schema.BodySchema{
+							Attributes: map[string]*schema.AttributeSchema{
+								"alias": {
+									ValueType:   cty.String,
+									IsRequired:  true,
+									Description: lang.Markdown("Name of the bind as declared by the service"),
+								},
+								"service": {
+									ValueType:   cty.String,
+									IsRequired:  true,
+									Description: lang.Markdown("Name of the service to bind to"),
+								},
+								"group": {
+									ValueType:   cty.String,
+									IsRequired:  true,
+									Description: lang.Markdown("Service group of the service to bind to"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var puppetProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Installs, configures and runs the Puppet agent on a remote resource"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"server": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("FQDN of the Puppet master that the agent is to connect to"),
+		},
+		"server_user": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("User that Bolt should connect to the server as, defaults to `root`"),
+		},
+		"os_type": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("OS type of the resource, `linux` or `windows`"),
+		},
+		"use_sudo": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use `sudo` when running commands on the resource"),
+		},
+		"autosign": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to automatically sign the Puppet agent's certificate, defaults to `true`"),
+		},
+		"open_source": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use the open source Puppet agent packages instead of Puppet Enterprise ones"),
+		},
+		"certname": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Certificate name of the Puppet agent, defaults to the FQDN of the resource"),
+		},
+		"extension_requests": {
+			ValueType:   cty.Map(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Map of extension requests to be embedded in the certificate signing request"),
+		},
+		"custom_attributes": {
+			ValueType:   cty.Map(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Map of custom attributes to be embedded in the certificate signing request"),
+		},
+		"environment": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Puppet environment of the agent, defaults to `production`"),
+		},
+		"bolt_timeout": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Timeout to wait for Bolt tasks to complete, defaults to `5m`"),
+		},
+	},
+}
+
+var saltMasterlessProvisioner = &schema.BodySchema{
+	Description: lang.Markdown("Provisions a resource via Salt states without a Salt master"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"local_state_tree": {
+			ValueType:   cty.String,
+			IsRequired:  true,
+			Description: lang.Markdown("Path to the local state tree to upload to the remote machine"),
+		},
+		"bootstrap_args": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Arguments to send to the bootstrap script"),
+		},
+		"disable_sudo": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to disable `sudo` when running commands on the remote machine"),
+		},
+		"custom_state": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("State to run instead of `highstate`"),
+		},
+		"minion_config_file": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Path to a local minion config file to upload to the remote machine"),
+		},
+		"local_pillar_roots": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Path to the local pillar roots to upload to the remote machine"),
+		},
+		"remote_pillar_roots": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Path on the remote machine to upload the local pillar roots to, defaults to `/srv/pillar`"),
+		},
+		"remote_state_tree": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Path on the remote machine to upload the local state tree to, defaults to `/srv/salt`"),
+		},
+		"salt_call_args": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Additional arguments to pass to `salt-call`"),
+		},
+		"log_level": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Log level of `salt-call`, defaults to `info`"),
+		},
+		"temp_config_dir": {
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Directory on the remote machine to upload configuration files to, defaults to `/tmp/salt`"),
+		},
+		"no_exit_on_failure": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to continue even if the Salt run fails"),
+		},
+		"skip_bootstrap": {
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to skip the bootstrap step, e.g. when Salt is already installed"),
+		},
+	},
+}
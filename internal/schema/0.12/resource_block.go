@@ -43,8 +43,9 @@ func resourceBlockSchema(v *version.Version) *schema.BlockSchema {
 				},
 			},
 			Blocks: map[string]*schema.BlockSchema{
-				"lifecycle":  lifecycleBlock,
-				"connection": connectionBlock,
+				"lifecycle":   lifecycleBlock,
+				"connection":  connectionBlock,
+				"provisioner": provisionerBlock(v),
 			},
 		},
 	}
@@ -87,48 +88,3 @@ var lifecycleBlock = &schema.BlockSchema{
 		},
 	},
 }
-
-var provisionerBlock = &schema.BlockSchema{
-	Description: lang.Markdown("Provisioner to model specific actions on the local machine or on a remote machine " +
-		"in order to prepare servers or other infrastructure objects for service"),
-	Labels: []*schema.LabelSchema{
-		{
-			Name:        "type",
-			Description: lang.PlainText("Type of provisioner to use, e.g. `remote-exec` or `file`"),
-			IsDepKey:    true,
-		},
-	},
-	Body: &schema.BodySchema{
-		Attributes: map[string]*schema.AttributeSchema{
-			"when": {
-				ValueType:  cty.DynamicPseudoType,
-				IsOptional: true,
-				Description: lang.Markdown("When to run the provisioner - `create` or `destroy`, defaults to `create` " +
-					"(i.e. after creation of the resource)"),
-			},
-			"on_failure": {
-				IsOptional: true,
-				ValueType:  cty.DynamicPseudoType,
-				Description: lang.Markdown("What to do when the provisioner run fails to finish - `fail` (default), " +
-					"or `continue` (ignore the error)"),
-			},
-		},
-		Blocks: map[string]*schema.BlockSchema{
-			"connection": connectionBlock,
-		},
-	},
-}
-
-var connectionBlock = &schema.BlockSchema{
-	Description: lang.Markdown("Connection block describing how the provisioner connects to the given instance"),
-	MaxItems:    1,
-	Body: &schema.BodySchema{
-		Attributes: map[string]*schema.AttributeSchema{
-			"type": {
-				ValueType:   cty.String,
-				IsOptional:  true,
-				Description: lang.Markdown("Connection type to use - `ssh` (default) or `winrm`"),
-			},
-		},
-	},
-}
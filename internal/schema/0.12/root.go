@@ -6,6 +6,7 @@ import (
 )
 
 var (
+	v0_12_2  = version.Must(version.NewVersion("0.12.2"))
 	v0_12_6  = version.Must(version.NewVersion("0.12.6"))
 	v0_12_18 = version.Must(version.NewVersion("0.12.18"))
 	v0_12_20 = version.Must(version.NewVersion("0.12.20"))
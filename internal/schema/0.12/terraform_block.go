@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/terraform-schema/internal/schema/backends"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -30,7 +31,8 @@ func terraformBlockSchema(v *version.Version) *schema.BlockSchema {
 							IsDepKey:    true,
 						},
 					},
-					MaxItems: 1,
+					MaxItems:      1,
+					DependentBody: backends.ConfigsAsDependentBodies(v),
 				},
 				"required_providers": {
 					Description: lang.Markdown("What provider version to use within this configuration"),
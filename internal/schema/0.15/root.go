@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+
+	v014_mod "github.com/hashicorp/terraform-schema/internal/schema/0.14"
+)
+
+// removedProvisioners represents vendor provisioners
+// which were removed from Terraform in 0.15
+var removedProvisioners = []string{
+	"chef",
+	"habitat",
+	"puppet",
+	"salt-masterless",
+}
+
+func ModuleSchema(v *version.Version) *schema.BodySchema {
+	bs := v014_mod.ModuleSchema(v)
+	bs.Blocks["terraform"] = terraformBlockSchema(v)
+
+	provisioners := bs.Blocks["resource"].Body.Blocks["provisioner"].DependentBody
+	for _, name := range removedProvisioners {
+		delete(provisioners, schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: name},
+			},
+		}))
+	}
+
+	return bs
+}
@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+
+	v015_mod "github.com/hashicorp/terraform-schema/internal/schema/0.15"
+)
+
+// ModuleSchema returns the module schema for Terraform 1.0.x
+// which carries no language changes compared to 0.15
+func ModuleSchema(v *version.Version) *schema.BodySchema {
+	return v015_mod.ModuleSchema(v)
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var cloudBlockSchema = &schema.BlockSchema{
+	Description: lang.Markdown("Terraform Cloud configuration, mutually exclusive with the `backend` block"),
+	MaxItems:    1,
+	Body: &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"organization": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the organization to connect to"),
+			},
+			"hostname": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Hostname of Terraform Cloud or Terraform Enterprise, defaults to `app.terraform.io`"),
+			},
+			"token": {
+				ValueType:  cty.String,
+				IsOptional: true,
+				Description: lang.Markdown("Token used to authenticate with the given hostname. " +
+					"It is recommended to use the CLI configuration file or `terraform login` instead."),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"workspaces": {
+				Description: lang.Markdown("Workspaces to use, selected either by exact `name` or by `tags`"),
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"name": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Name of a single workspace to use"),
+						},
+						"tags": {
+							ValueType:   cty.Set(cty.String),
+							IsOptional:  true,
+							Description: lang.Markdown("Set of tags to select workspaces by, e.g. `[\"app\"]`"),
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var movedBlockSchema = &schema.BlockSchema{
+	Description: lang.Markdown("Declaration to track an object which has moved from one address to another, " +
+		"e.g. after renaming a resource or moving it into a module"),
+	Body: &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"from": {
+				ValueType:   cty.DynamicPseudoType,
+				IsRequired:  true,
+				Description: lang.Markdown("Previous address of the object, e.g. `aws_instance.a`"),
+			},
+			"to": {
+				ValueType:   cty.DynamicPseudoType,
+				IsRequired:  true,
+				Description: lang.Markdown("New address of the object, e.g. `aws_instance.b`"),
+			},
+		},
+	},
+}
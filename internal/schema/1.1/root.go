@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+
+	v1_0_mod "github.com/hashicorp/terraform-schema/internal/schema/1.0"
+)
+
+func ModuleSchema(v *version.Version) *schema.BodySchema {
+	bs := v1_0_mod.ModuleSchema(v)
+	bs.Blocks["moved"] = movedBlockSchema
+	bs.Blocks["terraform"].Body.Blocks["cloud"] = cloudBlockSchema
+	return bs
+}
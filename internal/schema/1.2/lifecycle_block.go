@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var resourceLifecycleBlock = &schema.BlockSchema{
+	Description: lang.Markdown("Lifecycle customizations to change default resource behaviours during apply"),
+	Body: &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"create_before_destroy": {
+				ValueType:  cty.Bool,
+				IsOptional: true,
+				Description: lang.Markdown("Whether to reverse the default order of operations (destroy -> create) during apply " +
+					"when the resource requires replacement (cannot be updated in-place)"),
+			},
+			"prevent_destroy": {
+				ValueType:  cty.Bool,
+				IsOptional: true,
+				Description: lang.Markdown("Whether to prevent accidental destruction of the resource and cause Terraform " +
+					"to reject with an error any plan that would destroy the resource"),
+			},
+			"ignore_changes": {
+				ValueType:   cty.Set(cty.DynamicPseudoType),
+				IsOptional:  true,
+				Description: lang.Markdown("A set of fields (references) of which to ignore changes to, e.g. `tags`"),
+			},
+			"replace_triggered_by": {
+				ValueType:  cty.Set(cty.DynamicPseudoType),
+				IsOptional: true,
+				Description: lang.Markdown("Set of references to other resources or their attributes " +
+					"which, when changed, cause this resource to be replaced, e.g. `aws_ecs_service.svc.id`"),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"precondition":  preconditionBlock,
+			"postcondition": postconditionBlock,
+		},
+	},
+}
+
+var datasourceLifecycleBlock = &schema.BlockSchema{
+	Description: lang.Markdown("Lifecycle customizations, such as custom conditions checked when reading the data source"),
+	Body: &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"precondition":  preconditionBlock,
+			"postcondition": postconditionBlock,
+		},
+	},
+}
+
+var preconditionBlock = &schema.BlockSchema{
+	Description: lang.Markdown("Condition checked before the object is evaluated, " +
+		"to catch invalid assumptions about other objects early"),
+	Body: conditionBody,
+}
+
+var postconditionBlock = &schema.BlockSchema{
+	Description: lang.Markdown("Condition checked after the object is evaluated, " +
+		"to guarantee the result meets expectations, e.g. `self.private_dns != \"\"`"),
+	Body: conditionBody,
+}
+
+var conditionBody = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"condition": {
+			ValueType:   cty.Bool,
+			IsRequired:  true,
+			Description: lang.Markdown("Condition which must evaluate to `true` for the configuration to be considered valid"),
+		},
+		"error_message": {
+			ValueType:  cty.String,
+			IsRequired: true,
+			Description: lang.Markdown("Error message to present when the condition is not met, " +
+				"i.e. when `condition` evaluates to `false`"),
+		},
+	},
+}
@@ -0,0 +1,21 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+
+	v1_1_mod "github.com/hashicorp/terraform-schema/internal/schema/1.1"
+)
+
+func ModuleSchema(v *version.Version) *schema.BodySchema {
+	bs := v1_1_mod.ModuleSchema(v)
+
+	bs.Blocks["resource"].Body.Blocks["lifecycle"] = resourceLifecycleBlock
+	if bs.Blocks["data"].Body.Blocks == nil {
+		bs.Blocks["data"].Body.Blocks = make(map[string]*schema.BlockSchema, 0)
+	}
+	bs.Blocks["data"].Body.Blocks["lifecycle"] = datasourceLifecycleBlock
+	bs.Blocks["output"] = outputBlockSchema
+
+	return bs
+}
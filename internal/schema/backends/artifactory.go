@@ -0,0 +1,43 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func artifactoryBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Artifactory without locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"username": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password"),
+			},
+			"url": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Artifactory URL, e.g. `https://artifactory.example.com/artifactory`"),
+			},
+			"repo": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Repository name"),
+			},
+			"subpath": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path within the repository"),
+			},
+		},
+	}
+
+	return deprecateBackend(v, bs)
+}
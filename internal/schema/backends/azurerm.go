@@ -0,0 +1,116 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func azureRmBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Azure Blob Storage with locking via native blob leases"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"storage_account_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the storage account"),
+			},
+			"container_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the storage container"),
+			},
+			"key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the blob to store the state in"),
+			},
+			"environment": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Azure environment to use, e.g. `public` (default), `china`, `german` or `usgovernment`"),
+			},
+			"endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for Azure Resource Manager"),
+			},
+			"resource_group_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the resource group containing the storage account"),
+			},
+			"snapshot": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to snapshot the blob before each write"),
+			},
+			"access_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Access key of the storage account"),
+			},
+			"sas_token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("SAS token used to access the blob"),
+			},
+			"subscription_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Subscription ID in which the storage account exists"),
+			},
+			"tenant_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Tenant ID in which the subscription exists"),
+			},
+			"client_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Client ID of the Service Principal"),
+			},
+			"client_secret": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Client Secret of the Service Principal"),
+			},
+			"client_certificate_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the PFX file used as the Client Certificate of the Service Principal"),
+			},
+			"client_certificate_password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password associated with the Client Certificate"),
+			},
+			"use_msi": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to authenticate using Managed Service Identity (MSI)"),
+			},
+			"msi_endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a custom Managed Service Identity endpoint"),
+			},
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_15_0) {
+		bs.Attributes["use_azuread_auth"] = &schema.AttributeSchema{
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to use Azure Active Directory authentication to access the storage account"),
+		}
+		bs.Attributes["metadata_host"] = &schema.AttributeSchema{
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Hostname of the Azure Metadata Service, used to obtain the cloud environment"),
+		}
+	}
+
+	return bs
+}
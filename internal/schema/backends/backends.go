@@ -0,0 +1,92 @@
+package backends
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+var (
+	v0_12_2 = version.Must(version.NewVersion("0.12.2"))
+	v0_13_0 = version.Must(version.NewVersion("0.13.0"))
+	v0_14_0 = version.Must(version.NewVersion("0.14.0"))
+	v0_15_0 = version.Must(version.NewVersion("0.15.0"))
+	v1_2_3  = version.Must(version.NewVersion("1.2.3"))
+	v1_3_0  = version.Must(version.NewVersion("1.3.0"))
+)
+
+// Configs returns body schemas of all backends available
+// in the given version of Terraform, keyed by backend type.
+//
+// All attributes are optional and no blocks are required,
+// as any of them can be supplied via partial configuration
+// (terraform init -backend-config=...) instead.
+func Configs(v *version.Version) map[string]*schema.BodySchema {
+	v = releaseVersion(v)
+
+	configs := map[string]*schema.BodySchema{
+		"azurerm": azureRmBackend(v),
+		"consul":  consulBackend(v),
+		"gcs":     gcsBackend(v),
+		"http":    httpBackend(v),
+		"local":   localBackend(v),
+		"pg":      pgBackend(v),
+		"remote":  remoteBackend(v),
+		"s3":      s3Backend(v),
+	}
+
+	if v.GreaterThanOrEqual(v0_12_2) {
+		configs["oss"] = ossBackend(v)
+	}
+
+	if v.GreaterThanOrEqual(v0_13_0) {
+		configs["cos"] = cosBackend(v)
+		configs["kubernetes"] = kubernetesBackend(v)
+	}
+
+	if v.LessThan(v1_3_0) {
+		configs["artifactory"] = artifactoryBackend(v)
+		configs["etcd"] = etcdBackend(v)
+		configs["etcdv3"] = etcdv3Backend(v)
+		configs["manta"] = mantaBackend(v)
+		configs["swift"] = swiftBackend(v)
+	}
+
+	return configs
+}
+
+// ConfigsAsDependentBodies returns body schemas of all backends
+// available in the given version of Terraform, keyed by the label
+// of the backend block, such that they can be used as DependentBody
+func ConfigsAsDependentBodies(v *version.Version) map[schema.SchemaKey]*schema.BodySchema {
+	configs := Configs(v)
+
+	m := make(map[schema.SchemaKey]*schema.BodySchema, len(configs))
+	for name, bodySchema := range configs {
+		m[schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: name},
+			},
+		})] = bodySchema
+	}
+
+	return m
+}
+
+// releaseVersion strips any prerelease or metadata from the version,
+// assuming that prereleases come with the same backends as the release
+func releaseVersion(v *version.Version) *version.Version {
+	segments := v.Segments64()
+	return version.Must(version.NewVersion(fmt.Sprintf("%d.%d.%d",
+		segments[0], segments[1], segments[2])))
+}
+
+// deprecateBackend marks the given backend body as deprecated
+// for Terraform versions which announced its upcoming removal
+func deprecateBackend(v *version.Version, bs *schema.BodySchema) *schema.BodySchema {
+	if v.GreaterThanOrEqual(v1_2_3) {
+		bs.IsDeprecated = true
+	}
+	return bs
+}
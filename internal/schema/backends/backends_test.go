@@ -0,0 +1,101 @@
+package backends
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+)
+
+func TestConfigs_validate(t *testing.T) {
+	versions := []string{"0.12.0", "0.13.0", "0.14.0", "0.15.0", "1.2.3", "1.3.0"}
+
+	for _, v := range versions {
+		for name, bodySchema := range Configs(version.Must(version.NewVersion(v))) {
+			if err := bodySchema.Validate(); err != nil {
+				t.Fatalf("%s: %s: %s", v, name, err)
+			}
+		}
+	}
+}
+
+func TestConfigs_available(t *testing.T) {
+	testCases := []struct {
+		version          string
+		expectedBackends []string
+	}{
+		{
+			"0.12.0",
+			[]string{
+				"artifactory", "azurerm", "consul", "etcd", "etcdv3", "gcs", "http",
+				"local", "manta", "pg", "remote", "s3", "swift",
+			},
+		},
+		{
+			"0.12.2",
+			[]string{
+				"artifactory", "azurerm", "consul", "etcd", "etcdv3", "gcs", "http",
+				"local", "manta", "oss", "pg", "remote", "s3", "swift",
+			},
+		},
+		{
+			"0.13.0",
+			[]string{
+				"artifactory", "azurerm", "consul", "cos", "etcd", "etcdv3", "gcs", "http",
+				"kubernetes", "local", "manta", "oss", "pg", "remote", "s3", "swift",
+			},
+		},
+		{
+			"0.13.0-beta1",
+			[]string{
+				"artifactory", "azurerm", "consul", "cos", "etcd", "etcdv3", "gcs", "http",
+				"kubernetes", "local", "manta", "oss", "pg", "remote", "s3", "swift",
+			},
+		},
+		{
+			"1.2.9",
+			[]string{
+				"artifactory", "azurerm", "consul", "cos", "etcd", "etcdv3", "gcs", "http",
+				"kubernetes", "local", "manta", "oss", "pg", "remote", "s3", "swift",
+			},
+		},
+		{
+			"1.3.0",
+			[]string{
+				"azurerm", "consul", "cos", "gcs", "http",
+				"kubernetes", "local", "oss", "pg", "remote", "s3",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.version), func(t *testing.T) {
+			configs := Configs(version.Must(version.NewVersion(tc.version)))
+			names := make([]string, 0, len(configs))
+			for name := range configs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			if diff := cmp.Diff(tc.expectedBackends, names); diff != "" {
+				t.Fatalf("backends mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigs_deprecated(t *testing.T) {
+	for _, name := range []string{"etcd", "swift"} {
+		v := version.Must(version.NewVersion("1.2.2"))
+		if Configs(v)[name].IsDeprecated {
+			t.Fatalf("%s backend not expected to be deprecated in %s", name, v)
+		}
+
+		v = version.Must(version.NewVersion("1.2.3"))
+		if !Configs(v)[name].IsDeprecated {
+			t.Fatalf("%s backend expected to be deprecated in %s", name, v)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func consulBackend(v *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Consul KV store with locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path in the Consul KV store to store the state in"),
+			},
+			"access_token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Access token for a Consul ACL"),
+			},
+			"address": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("DNS name and port of the Consul endpoint, e.g. `consul.example.com:8500`"),
+			},
+			"scheme": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Scheme to communicate with Consul, `http` or `https`"),
+			},
+			"datacenter": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Datacenter to use, defaults to that of the agent"),
+			},
+			"http_auth": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("HTTP Basic Authentication credentials, e.g. `user:pass`"),
+			},
+			"gzip": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to compress the state data using gzip"),
+			},
+			"lock": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable state locking, defaults to `true`"),
+			},
+			"ca_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded CA certificate file used to verify the Consul server"),
+			},
+			"cert_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded certificate provided to the server for client authentication"),
+			},
+			"key_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded private key, required if `cert_file` is specified"),
+			},
+		},
+	}
+}
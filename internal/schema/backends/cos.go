@@ -0,0 +1,56 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func cosBackend(v *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Tencent Cloud Object Storage (COS) with locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"secret_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Secret ID of Tencent Cloud"),
+			},
+			"secret_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Secret key of Tencent Cloud"),
+			},
+			"region": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Region of the COS bucket"),
+			},
+			"bucket": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the COS bucket, in the format of `bucket-appid`"),
+			},
+			"prefix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Directory for saving the state file in the bucket, defaults to `env:`"),
+			},
+			"key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path for saving the state file in the bucket, defaults to `terraform.tfstate`"),
+			},
+			"encrypt": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable server side encryption of the state file"),
+			},
+			"acl": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Object ACL to be applied to the state file, `private` (default) or `public-read`"),
+			},
+		},
+	}
+}
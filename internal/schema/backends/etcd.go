@@ -0,0 +1,38 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func etcdBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("etcd v2 (without locking)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path at which to store the state"),
+			},
+			"endpoints": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Space-separated list of the etcd endpoints"),
+			},
+			"username": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username used to connect to the etcd cluster"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password used to connect to the etcd cluster"),
+			},
+		},
+	}
+
+	return deprecateBackend(v, bs)
+}
@@ -0,0 +1,66 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func etcdv3Backend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("etcd v3 with locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"endpoints": {
+				ValueType:   cty.List(cty.String),
+				IsOptional:  true,
+				Description: lang.Markdown("List of the etcd endpoints in `scheme://host:port` format"),
+			},
+			"username": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username used to connect to the etcd cluster"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password used to connect to the etcd cluster"),
+			},
+			"prefix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Prefix to prepend to keys stored in etcd"),
+			},
+			"lock": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable state locking, defaults to `true`"),
+			},
+			"cacert_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded CA bundle used to verify the certificates of the etcd server"),
+			},
+			"cert_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded certificate provided to the server for client authentication"),
+			},
+			"key_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a PEM-encoded key provided to the server for client authentication"),
+			},
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_13_0) {
+		bs.Attributes["max_request_bytes"] = &schema.AttributeSchema{
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Maximum size of a request to etcd in bytes, defaults to 2MiB"),
+		}
+	}
+
+	return deprecateBackend(v, bs)
+}
@@ -0,0 +1,56 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func gcsBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Google Cloud Storage (GCS) with locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"bucket": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the GCS bucket"),
+			},
+			"prefix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Directory where state files will be saved inside the bucket"),
+			},
+			"credentials": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Local path to Google Cloud Platform account credentials in JSON format"),
+			},
+			"access_token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Temporary OAuth 2.0 access token, takes precedence over `credentials`"),
+			},
+			"encryption_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("32 byte base64 encoded customer supplied encryption key used to encrypt the state"),
+			},
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_14_0) {
+		bs.Attributes["impersonate_service_account"] = &schema.AttributeSchema{
+			ValueType:   cty.String,
+			IsOptional:  true,
+			Description: lang.Markdown("Service account to impersonate for accessing the bucket"),
+		}
+		bs.Attributes["impersonate_service_account_delegates"] = &schema.AttributeSchema{
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Delegation chain for impersonating the service account"),
+		}
+	}
+
+	return bs
+}
@@ -0,0 +1,81 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func httpBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("REST client with optional locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"address": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("REST endpoint"),
+			},
+			"update_method": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("HTTP method to use when updating state, defaults to `POST`"),
+			},
+			"lock_address": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Address of the lock REST endpoint, locking is disabled when not set"),
+			},
+			"lock_method": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("HTTP method to use when locking, defaults to `LOCK`"),
+			},
+			"unlock_address": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Address of the unlock REST endpoint, unlocking is disabled when not set"),
+			},
+			"unlock_method": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("HTTP method to use when unlocking, defaults to `UNLOCK`"),
+			},
+			"username": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username for HTTP basic authentication"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password for HTTP basic authentication"),
+			},
+			"skip_cert_verification": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to skip TLS verification"),
+			},
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_13_0) {
+		bs.Attributes["retry_max"] = &schema.AttributeSchema{
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Number of HTTP request retries, defaults to `2`"),
+		}
+		bs.Attributes["retry_wait_min"] = &schema.AttributeSchema{
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Minimum time in seconds to wait between HTTP request attempts, defaults to `1`"),
+		}
+		bs.Attributes["retry_wait_max"] = &schema.AttributeSchema{
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Maximum time in seconds to wait between HTTP request attempts, defaults to `30`"),
+		}
+	}
+
+	return bs
+}
@@ -0,0 +1,142 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func kubernetesBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Kubernetes secret with locking via a lease"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"secret_suffix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Suffix used when creating secrets, e.g. `tfstate-{workspace}-{secret_suffix}`"),
+			},
+			"labels": {
+				ValueType:   cty.Map(cty.String),
+				IsOptional:  true,
+				Description: lang.Markdown("Map of additional labels to be applied to the secret and lease"),
+			},
+			"namespace": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Namespace to store the secret and lease in, defaults to `default`"),
+			},
+			"in_cluster_config": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to use the service account Kubernetes gives to pods"),
+			},
+			"host": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Hostname (in form of URI) of the Kubernetes API"),
+			},
+			"username": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username to use for HTTP basic authentication"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password to use for HTTP basic authentication"),
+			},
+			"insecure": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether the server should be accessed without verifying the TLS certificate"),
+			},
+			"client_certificate": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("PEM-encoded client certificate for TLS authentication"),
+			},
+			"client_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("PEM-encoded client certificate key for TLS authentication"),
+			},
+			"cluster_ca_certificate": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("PEM-encoded root certificates bundle for TLS authentication"),
+			},
+			"config_path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the kube config file, e.g. `~/.kube/config`"),
+			},
+			"config_context": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Context to choose from the config file"),
+			},
+			"config_context_auth_info": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Authentication info context of the kube config (name of the kubeconfig user)"),
+			},
+			"config_context_cluster": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Cluster context of the kube config (name of the kubeconfig cluster)"),
+			},
+			"token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Token of your service account"),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"exec": {
+				Description: lang.Markdown("Configuration for an external command to obtain credentials"),
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"api_version": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("API version to use when decoding the ExecCredentials resource"),
+						},
+						"command": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Command to execute"),
+						},
+						"args": {
+							ValueType:   cty.List(cty.String),
+							IsOptional:  true,
+							Description: lang.Markdown("List of arguments to pass when executing the command"),
+						},
+						"env": {
+							ValueType:   cty.Map(cty.String),
+							IsOptional:  true,
+							Description: lang.Markdown("Map of environment variables to set when executing the command"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if v.LessThan(v0_15_0) {
+		bs.Attributes["load_config_file"] = &schema.AttributeSchema{
+			ValueType:   cty.Bool,
+			IsOptional:  true,
+			Description: lang.Markdown("Whether to load the local kube config file, defaults to `true`"),
+		}
+	} else {
+		bs.Attributes["config_paths"] = &schema.AttributeSchema{
+			ValueType:   cty.List(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("List of paths to kube config files"),
+		}
+	}
+
+	return bs
+}
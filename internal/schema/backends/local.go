@@ -0,0 +1,26 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func localBackend(v *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Local filesystem with locking via system APIs"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the state file, defaults to `terraform.tfstate`"),
+			},
+			"workspace_dir": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to non-default workspaces"),
+			},
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func mantaBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Joyent Triton Manta with locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"account": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the Manta account"),
+			},
+			"user": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username of the Triton account used to authenticate"),
+			},
+			"url": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Manta API endpoint, defaults to `https://us-east.manta.joyent.com`"),
+			},
+			"key_material": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Private key of an SSH key associated with the Triton account"),
+			},
+			"key_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Fingerprint of the public key matching the `key_material`"),
+			},
+			"insecure_skip_tls_verify": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to skip TLS verification"),
+			},
+			"path": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path relative to your private storage directory (`/$MANTA_USER/stor`) to store the state in"),
+			},
+			"object_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the state object, defaults to `terraform.tfstate`"),
+			},
+		},
+	}
+
+	return deprecateBackend(v, bs)
+}
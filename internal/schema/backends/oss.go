@@ -0,0 +1,121 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func ossBackend(v *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Alibaba Cloud Object Storage Service (OSS) with locking via TableStore"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"access_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Alibaba Cloud access key"),
+			},
+			"secret_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Alibaba Cloud secret access key"),
+			},
+			"security_token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Security token of temporary access credentials (STS)"),
+			},
+			"ecs_role_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("RAM role name attached to the ECS instance to obtain credentials from"),
+			},
+			"region": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Region of the OSS bucket"),
+			},
+			"endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the OSS API"),
+			},
+			"bucket": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the OSS bucket"),
+			},
+			"prefix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Directory for saving the state file in the bucket, defaults to `env:`"),
+			},
+			"key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path for saving the state file in the bucket, defaults to `terraform.tfstate`"),
+			},
+			"tablestore_endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the TableStore API"),
+			},
+			"tablestore_table": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the TableStore table to use for state locking and consistency"),
+			},
+			"encrypt": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable server side encryption of the state file"),
+			},
+			"acl": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Object ACL to be applied to the state file"),
+			},
+			"profile": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Alibaba Cloud profile name as set in the shared credentials file"),
+			},
+			"shared_credentials_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the shared credentials file, defaults to `~/.aliyun/config.json`"),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"assume_role": {
+				Description: lang.Markdown("RAM role to assume"),
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"role_arn": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("ARN of the RAM role to assume"),
+						},
+						"session_name": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Session name to use when assuming the role"),
+						},
+						"policy": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Permissions policy further restricting the permissions of the assumed role"),
+						},
+						"session_expiration": {
+							ValueType:   cty.Number,
+							IsOptional:  true,
+							Description: lang.Markdown("Time in seconds after which the session expires"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
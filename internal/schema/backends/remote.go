@@ -0,0 +1,54 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func remoteBackend(v *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Terraform Cloud or Terraform Enterprise, which stores state " +
+			"and may be used to run operations remotely"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"hostname": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Remote backend hostname to connect to, defaults to `app.terraform.io`"),
+			},
+			"organization": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the organization containing the targeted workspace(s)"),
+			},
+			"token": {
+				ValueType:  cty.String,
+				IsOptional: true,
+				Description: lang.Markdown("Token used to authenticate with the remote backend. " +
+					"It is recommended to use the CLI configuration file instead."),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"workspaces": {
+				Description: lang.Markdown("Workspaces to use in the remote backend, " +
+					"either a single one by `name` or multiple ones by `prefix`"),
+				MaxItems: 1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"name": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Full name of one remote workspace"),
+						},
+						"prefix": {
+							ValueType:   cty.String,
+							IsOptional:  true,
+							Description: lang.Markdown("Prefix used in the names of one or more remote workspaces"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func s3Backend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("Amazon S3 with optional locking via DynamoDB"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"bucket": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the S3 bucket"),
+			},
+			"key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the state file inside the bucket"),
+			},
+			"region": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("AWS region of the S3 bucket"),
+			},
+			"endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the S3 API"),
+			},
+			"encrypt": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable server side encryption of the state file"),
+			},
+			"acl": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Canned ACL to be applied to the state file"),
+			},
+			"access_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("AWS access key"),
+			},
+			"secret_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("AWS secret key"),
+			},
+			"kms_key_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("ARN of a KMS key to use for encrypting the state"),
+			},
+			"lock_table": {
+				ValueType:    cty.String,
+				IsOptional:   true,
+				IsDeprecated: true,
+				Description:  lang.Markdown("**DEPRECATED:** Use `dynamodb_table` instead"),
+			},
+			"dynamodb_table": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the DynamoDB table to use for state locking and consistency"),
+			},
+			"dynamodb_endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the DynamoDB API"),
+			},
+			"profile": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("AWS profile name as set in the shared credentials file"),
+			},
+			"shared_credentials_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to the shared credentials file, defaults to `~/.aws/credentials`"),
+			},
+			"token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Multi-Factor Authentication (MFA) token"),
+			},
+			"skip_credentials_validation": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to skip credentials validation via the STS API"),
+			},
+			"skip_region_validation": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to skip validation of the provided region name"),
+			},
+			"skip_metadata_api_check": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to skip the AWS Metadata API check"),
+			},
+			"sse_customer_key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Key to use for encrypting state with Server-Side Encryption with Customer-Provided Keys (SSE-C)"),
+			},
+			"role_arn": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("ARN of an IAM role to assume"),
+			},
+			"session_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Session name to use when assuming the role"),
+			},
+			"external_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("External ID to use when assuming the role"),
+			},
+			"assume_role_policy": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("IAM policy JSON describing further restricting permissions for the IAM role being assumed"),
+			},
+			"workspace_key_prefix": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Prefix applied to the state path inside the bucket for non-default workspaces, defaults to `env:`"),
+			},
+			"force_path_style": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable path-style S3 URLs"),
+			},
+			"max_retries": {
+				ValueType:   cty.Number,
+				IsOptional:  true,
+				Description: lang.Markdown("Maximum number of times an AWS API request is retried on retryable failure, defaults to `5`"),
+			},
+			"iam_endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the AWS IAM API"),
+			},
+			"sts_endpoint": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Custom endpoint for the AWS STS API"),
+			},
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_14_0) {
+		bs.Attributes["assume_role_duration_seconds"] = &schema.AttributeSchema{
+			ValueType:   cty.Number,
+			IsOptional:  true,
+			Description: lang.Markdown("Number of seconds to restrict the assume role session duration"),
+		}
+		bs.Attributes["assume_role_policy_arns"] = &schema.AttributeSchema{
+			ValueType:   cty.Set(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Set of IAM policy ARNs further restricting permissions for the IAM role being assumed"),
+		}
+		bs.Attributes["assume_role_tags"] = &schema.AttributeSchema{
+			ValueType:   cty.Map(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Map of assume role session tags"),
+		}
+		bs.Attributes["assume_role_transitive_tag_keys"] = &schema.AttributeSchema{
+			ValueType:   cty.Set(cty.String),
+			IsOptional:  true,
+			Description: lang.Markdown("Set of assume role session tag keys to pass to any subsequent sessions"),
+		}
+	}
+
+	return bs
+}
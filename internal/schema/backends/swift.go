@@ -0,0 +1,119 @@
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func swiftBackend(v *version.Version) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Description: lang.Markdown("OpenStack Swift with optional locking"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"auth_url": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Identity authentication URL"),
+			},
+			"container": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the container to create for storing the state"),
+			},
+			"path": {
+				ValueType:    cty.String,
+				IsOptional:   true,
+				IsDeprecated: true,
+				Description:  lang.Markdown("**DEPRECATED:** Use `container` instead"),
+			},
+			"user_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Username to login with"),
+			},
+			"user_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("User ID to login with"),
+			},
+			"password": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Password to login with"),
+			},
+			"token": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Access token to login with instead of a username and password"),
+			},
+			"region_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Region of the Swift API"),
+			},
+			"tenant_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("ID of the tenant (project) to login with"),
+			},
+			"tenant_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the tenant (project) to login with"),
+			},
+			"domain_id": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("ID of the domain to scope to (Identity v3)"),
+			},
+			"domain_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the domain to scope to (Identity v3)"),
+			},
+			"insecure": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to trust self-signed certificates"),
+			},
+			"cacert_file": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a custom CA certificate"),
+			},
+			"cert": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a client certificate for two-way TLS"),
+			},
+			"key": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Path to a client private key for two-way TLS"),
+			},
+			"archive_container": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the container to archive previous state versions in"),
+			},
+			"expire_after": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Duration after which state objects expire, e.g. `48h`"),
+			},
+			"lock": {
+				ValueType:   cty.Bool,
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to enable state locking"),
+			},
+			"state_name": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Name of the state object in the container, defaults to `tfstate.tf`"),
+			},
+		},
+	}
+
+	return deprecateBackend(v, bs)
+}
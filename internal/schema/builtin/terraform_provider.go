@@ -0,0 +1,111 @@
+package builtin
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/terraform-schema/internal/bodytype"
+	"github.com/hashicorp/terraform-schema/internal/schema/backends"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var v1_4_0 = version.Must(version.NewVersion("1.4.0"))
+
+// TerraformProviderSchema represents schema of the built-in
+// terraform provider (terraform.io/builtin/terraform),
+// as shipped with a particular version of Terraform
+type TerraformProviderSchema struct {
+	Provider    *schema.BodySchema
+	Resources   map[string]*schema.BodySchema
+	DataSources map[string]*schema.BodySchema
+
+	// RemoteStateByBackend represents full bodies of the
+	// terraform_remote_state data source with "config"
+	// specific to the backend type used as key
+	RemoteStateByBackend map[string]*schema.BodySchema
+}
+
+// TerraformProvider returns schema of the built-in terraform provider
+// as shipped with the given version of Terraform
+func TerraformProvider(v *version.Version) *TerraformProviderSchema {
+	ps := &TerraformProviderSchema{
+		Provider:  schema.NewBodySchema(),
+		Resources: make(map[string]*schema.BodySchema, 0),
+		DataSources: map[string]*schema.BodySchema{
+			"terraform_remote_state": remoteStateDataSource(cty.DynamicPseudoType),
+		},
+		RemoteStateByBackend: make(map[string]*schema.BodySchema, 0),
+	}
+
+	for backendType, backendSchema := range backends.Configs(v) {
+		ps.RemoteStateByBackend[backendType] = remoteStateDataSource(bodytype.ObjectType(backendSchema))
+	}
+
+	if v.GreaterThanOrEqual(v1_4_0) {
+		ps.Resources["terraform_data"] = dataResource
+	}
+
+	return ps
+}
+
+func remoteStateDataSource(configType cty.Type) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: lang.Markdown("Retrieves the root module output values from a Terraform state snapshot " +
+			"stored in a remote backend"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"backend": {
+				ValueType:   cty.String,
+				IsRequired:  true,
+				Description: lang.Markdown("Backend type to retrieve the state snapshot from, e.g. `s3`"),
+				IsDepKey:    true,
+			},
+			"config": {
+				ValueType:   configType,
+				IsOptional:  true,
+				Description: lang.Markdown("Configuration of the backend, as would be used inside the `backend` block"),
+			},
+			"defaults": {
+				ValueType:   cty.DynamicPseudoType,
+				IsOptional:  true,
+				Description: lang.Markdown("Default values for outputs, used in case the state file is empty or lacks a required output"),
+			},
+			"outputs": {
+				ValueType:   cty.DynamicPseudoType,
+				IsComputed:  true,
+				Description: lang.Markdown("Root module output values from the remote state snapshot"),
+			},
+			"workspace": {
+				ValueType:   cty.String,
+				IsOptional:  true,
+				Description: lang.Markdown("Workspace to retrieve the state snapshot from, defaults to `default`"),
+			},
+		},
+	}
+}
+
+var dataResource = &schema.BodySchema{
+	Description: lang.Markdown("Stores arbitrary values which follow the usual resource lifecycle, " +
+		"e.g. to trigger replacement of other resources"),
+	Attributes: map[string]*schema.AttributeSchema{
+		"input": {
+			ValueType:   cty.DynamicPseudoType,
+			IsOptional:  true,
+			Description: lang.Markdown("Value to store, reflected in `output` after apply"),
+		},
+		"output": {
+			ValueType:   cty.DynamicPseudoType,
+			IsComputed:  true,
+			Description: lang.Markdown("Value of `input` as stored after apply"),
+		},
+		"triggers_replace": {
+			ValueType:   cty.DynamicPseudoType,
+			IsOptional:  true,
+			Description: lang.Markdown("Value which causes the resource to be replaced when changed"),
+		},
+		"id": {
+			ValueType:   cty.String,
+			IsComputed:  true,
+			Description: lang.Markdown("Unique ID of the resource"),
+		},
+	},
+}
@@ -0,0 +1,61 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestTerraformProvider_validate(t *testing.T) {
+	versions := []string{"0.12.0", "0.13.0", "0.15.0", "1.4.0"}
+
+	for _, v := range versions {
+		ps := TerraformProvider(version.Must(version.NewVersion(v)))
+		for name, bodySchema := range ps.DataSources {
+			if err := bodySchema.Validate(); err != nil {
+				t.Fatalf("%s: %s: %s", v, name, err)
+			}
+		}
+		for name, bodySchema := range ps.Resources {
+			if err := bodySchema.Validate(); err != nil {
+				t.Fatalf("%s: %s: %s", v, name, err)
+			}
+		}
+		for name, bodySchema := range ps.RemoteStateByBackend {
+			if err := bodySchema.Validate(); err != nil {
+				t.Fatalf("%s: %s: %s", v, name, err)
+			}
+		}
+	}
+}
+
+func TestTerraformProvider_terraformData(t *testing.T) {
+	ps := TerraformProvider(version.Must(version.NewVersion("1.3.0")))
+	if _, ok := ps.Resources["terraform_data"]; ok {
+		t.Fatal("terraform_data not expected in 1.3.0")
+	}
+
+	ps = TerraformProvider(version.Must(version.NewVersion("1.4.0")))
+	if _, ok := ps.Resources["terraform_data"]; !ok {
+		t.Fatal("terraform_data expected in 1.4.0")
+	}
+}
+
+func TestTerraformProvider_remoteStateConfig(t *testing.T) {
+	ps := TerraformProvider(version.Must(version.NewVersion("0.14.0")))
+
+	configType := ps.RemoteStateByBackend["remote"].Attributes["config"].ValueType
+	if !configType.HasAttribute("workspaces") {
+		t.Fatalf("expected workspaces in remote config, given: %s", configType.FriendlyName())
+	}
+	if !configType.AttributeOptional("workspaces") {
+		t.Fatal("expected workspaces to be optional in remote config")
+	}
+	if !configType.AttributeType("workspaces").IsObjectType() {
+		t.Fatalf("expected workspaces to be an object, given: %s",
+			configType.AttributeType("workspaces").FriendlyName())
+	}
+	if !configType.AttributeOptional("hostname") {
+		t.Fatal("expected hostname to be optional in remote config")
+	}
+}
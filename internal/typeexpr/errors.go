@@ -0,0 +1,34 @@
+package typeexpr
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FormatConversionError includes the path (if any)
+// to the invalid part of the value in the error message
+func FormatConversionError(err error) string {
+	if pathErr, ok := err.(cty.PathError); ok && len(pathErr.Path) > 0 {
+		return fmt.Sprintf("%s: %s", formatPath(pathErr.Path), pathErr.Error())
+	}
+	return err.Error()
+}
+
+func formatPath(path cty.Path) string {
+	s := ""
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			s += "." + step.Name
+		case cty.IndexStep:
+			switch step.Key.Type() {
+			case cty.String:
+				s += fmt.Sprintf("[%q]", step.Key.AsString())
+			case cty.Number:
+				s += fmt.Sprintf("[%s]", step.Key.AsBigFloat().Text('f', -1))
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,405 @@
+// Package typeexpr decodes type constraints of variables
+// (e.g. list(object({ name = string }))) into cty types.
+//
+// It follows the syntax supported by Terraform, including optional
+// object attributes, which (unlike hcl's typeexpr) are supported
+// depending on the Terraform version and enabled experiments.
+package typeexpr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const invalidTypeSummary = "Invalid type specification"
+
+// OptionalAttrsExperiment is the name of the experiment which
+// enables optional object attributes in Terraform 0.14 up to 1.2
+const OptionalAttrsExperiment = "module_variable_optional_attrs"
+
+var (
+	v0_14_0 = version.Must(version.NewVersion("0.14.0"))
+	v0_15_0 = version.Must(version.NewVersion("0.15.0"))
+	v1_3_0  = version.Must(version.NewVersion("1.3.0"))
+)
+
+// Options controls which syntax is accepted
+type Options struct {
+	// AllowOptionalAttrs allows optional(TYPE) to be used
+	// within object type constraints
+	AllowOptionalAttrs bool
+
+	// AllowOptionalDefaults allows a default value
+	// to be declared via optional(TYPE, DEFAULT)
+	AllowOptionalDefaults bool
+
+	// AllowQuotedTypes allows legacy type constraints given
+	// as quoted strings ("string", "list" or "map"), which are
+	// deprecated, i.e. reported as warnings
+	AllowQuotedTypes bool
+
+	// optionalAttrsErr describes why optional attributes
+	// are not allowed (if they are not)
+	optionalAttrsErr string
+}
+
+// OptionsForVersion returns options reflecting the syntax supported
+// by the given Terraform version with the given experiments enabled.
+//
+// If the version is unknown (nil), all syntax is allowed.
+func OptionsForVersion(v *version.Version, experiments map[string]bool) Options {
+	opts := optionalAttrsOptions(v, experiments)
+	opts.AllowQuotedTypes = v == nil || v.LessThan(v0_15_0)
+	return opts
+}
+
+func optionalAttrsOptions(v *version.Version, experiments map[string]bool) Options {
+	if v == nil || v.GreaterThanOrEqual(v1_3_0) {
+		return Options{
+			AllowOptionalAttrs:    true,
+			AllowOptionalDefaults: true,
+		}
+	}
+
+	if v.LessThan(v0_14_0) {
+		return Options{
+			optionalAttrsErr: fmt.Sprintf("Optional object attributes are not supported in Terraform %s. "+
+				"They require Terraform 0.14 or later.", v.String()),
+		}
+	}
+
+	if !experiments[OptionalAttrsExperiment] {
+		return Options{
+			optionalAttrsErr: fmt.Sprintf("Optional object attributes are experimental in Terraform %s "+
+				"and must be enabled via experiments = [%s] in the terraform block.",
+				v.String(), OptionalAttrsExperiment),
+		}
+	}
+
+	return Options{
+		AllowOptionalAttrs: true,
+	}
+}
+
+// TypeConstraint decodes the given type expression
+// (as used in the type argument of a variable) into cty.Type.
+//
+// cty.DynamicPseudoType is returned for any (part of)
+// the expression which is invalid, along with diagnostics.
+func TypeConstraint(expr hcl.Expression, opts Options) (cty.Type, hcl.Diagnostics) {
+	if tExpr, ok := expr.(*hclsyntax.TemplateExpr); ok {
+		return getQuotedType(tExpr, opts)
+	}
+	return getType(expr, opts)
+}
+
+// quotedTypes maps legacy quoted type constraints to types
+// which Terraform 0.12 up to 0.14 interprets them as
+var quotedTypes = map[string]cty.Type{
+	"string": cty.String,
+	"list":   cty.List(cty.DynamicPseudoType),
+	"map":    cty.Map(cty.DynamicPseudoType),
+}
+
+// getQuotedType decodes legacy type constraints given as quoted strings,
+// as supported by Terraform 0.11 and earlier, which are accepted
+// with a warning by Terraform 0.12 up to 0.14.
+//
+// Such constraints are decoded into the type Terraform interprets
+// them as, even where they are no longer supported, so that
+// the type still reflects the intent of the author.
+func getQuotedType(expr *hclsyntax.TemplateExpr, opts Options) (cty.Type, hcl.Diagnostics) {
+	var kw string
+	if expr.IsStringLiteral() {
+		val, _ := expr.Value(nil)
+		kw = val.AsString()
+	}
+
+	ty, ok := quotedTypes[kw]
+
+	var hint string
+	switch {
+	case kw == "string":
+		hint = fmt.Sprintf("remove the quotes around %q.", kw)
+	case ok:
+		hint = fmt.Sprintf("remove the quotes around %q and write %s(string) instead "+
+			"to explicitly indicate that the %s elements are strings.", kw, kw, kw)
+	default:
+		return cty.DynamicPseudoType, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid legacy variable type hint",
+			Detail: "The legacy variable type hint form, using a quoted string, " +
+				"allows only the values \"string\", \"list\", and \"map\". " +
+				"To provide a full type expression, remove the surrounding quotes " +
+				"and give the type expression directly.",
+			Subject: expr.Range().Ptr(),
+		}}
+	}
+
+	if !opts.AllowQuotedTypes {
+		return ty, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid quoted type constraints",
+			Detail: "Terraform 0.11 and earlier required type constraints to be given in quotes, " +
+				"but that form is no longer supported. To fix this, " + hint,
+			Subject: expr.Range().Ptr(),
+		}}
+	}
+
+	return ty, hcl.Diagnostics{{
+		Severity: hcl.DiagWarning,
+		Summary:  "Quoted type constraints are deprecated",
+		Detail: "Terraform 0.11 and earlier required type constraints to be given in quotes, " +
+			"but that form is now deprecated and will be removed in a future version of Terraform. " +
+			"To silence this warning, " + hint,
+		Subject: expr.Range().Ptr(),
+	}}
+}
+
+func getType(expr hcl.Expression, opts Options) (cty.Type, hcl.Diagnostics) {
+	kw := hcl.ExprAsKeyword(expr)
+	switch kw {
+	case "bool":
+		return cty.Bool, nil
+	case "string":
+		return cty.String, nil
+	case "number":
+		return cty.Number, nil
+	case "any":
+		return cty.DynamicPseudoType, nil
+	case "list", "map", "set":
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			fmt.Sprintf("The %s type constructor requires one argument specifying the element type.", kw))
+	case "object":
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			"The object type constructor requires one argument specifying the attribute types and values as a map.")
+	case "tuple":
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			"The tuple type constructor requires one argument specifying the element types as a list.")
+	case "optional":
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			"The optional type modifier requires an argument specifying the attribute type.")
+	case "":
+		// not a keyword, try processing as a call
+	default:
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			fmt.Sprintf("The keyword %q is not a valid type specification.", kw))
+	}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() {
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			"A type specification is either a primitive type keyword (bool, number, string) "+
+				"or a complex type constructor call, like list(string).")
+	}
+
+	switch call.Name {
+	case "bool", "string", "number", "any":
+		return cty.DynamicPseudoType, invalidType(call.ArgsRange,
+			fmt.Sprintf("Primitive type keyword %q does not expect arguments.", call.Name))
+	case "optional":
+		// optional(...) is only valid as an object attribute type
+		// and is handled by getObjectType
+		return cty.DynamicPseudoType, invalidType(call.NameRange,
+			"The optional type modifier is only valid for object attribute types, "+
+				"e.g. object({ name = optional(string) }).")
+	}
+
+	if len(call.Arguments) != 1 {
+		contextRange := call.ArgsRange
+		subjectRange := call.ArgsRange
+		if len(call.Arguments) > 1 {
+			// If we have too many arguments (as opposed to too _few_) then
+			// we'll highlight the extraneous arguments as the diagnostic
+			// subject.
+			subjectRange = hcl.RangeBetween(call.Arguments[1].Range(), call.Arguments[len(call.Arguments)-1].Range())
+		}
+
+		var detail string
+		switch call.Name {
+		case "list", "set", "map":
+			detail = fmt.Sprintf("The %s type constructor requires one argument specifying the element type.", call.Name)
+		case "object":
+			detail = "The object type constructor requires one argument specifying the attribute types and values as a map."
+		case "tuple":
+			detail = "The tuple type constructor requires one argument specifying the element types as a list."
+		default:
+			return cty.DynamicPseudoType, invalidType(call.NameRange,
+				fmt.Sprintf("Keyword %q is not a valid type constructor.", call.Name))
+		}
+		return cty.DynamicPseudoType, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  invalidTypeSummary,
+			Detail:   detail,
+			Subject:  &subjectRange,
+			Context:  &contextRange,
+		}}
+	}
+
+	arg := call.Arguments[0]
+
+	switch call.Name {
+	case "list":
+		ety, diags := getType(arg, opts)
+		return cty.List(ety), diags
+	case "set":
+		ety, diags := getType(arg, opts)
+		return cty.Set(ety), diags
+	case "map":
+		ety, diags := getType(arg, opts)
+		return cty.Map(ety), diags
+	case "object":
+		return getObjectType(arg, opts)
+	case "tuple":
+		elemExprs, diags := hcl.ExprList(arg)
+		if diags.HasErrors() {
+			return cty.DynamicPseudoType, invalidType(arg.Range(),
+				"Tuple type constructor requires a list of element types.")
+		}
+		etys := make([]cty.Type, len(elemExprs))
+		for i, ex := range elemExprs {
+			ety, elemDiags := getType(ex, opts)
+			etys[i] = ety
+			diags = append(diags, elemDiags...)
+		}
+		return cty.Tuple(etys), diags
+	}
+
+	return cty.DynamicPseudoType, invalidType(call.NameRange,
+		fmt.Sprintf("Keyword %q is not a valid type constructor.", call.Name))
+}
+
+func getObjectType(expr hcl.Expression, opts Options) (cty.Type, hcl.Diagnostics) {
+	attrDefs, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return cty.DynamicPseudoType, invalidType(expr.Range(),
+			"Object type constructor requires a map whose keys are attribute names "+
+				"and whose values are the corresponding attribute types.")
+	}
+
+	atys := make(map[string]cty.Type, len(attrDefs))
+	optional := make([]string, 0)
+
+	for _, attrDef := range attrDefs {
+		attrName := hcl.ExprAsKeyword(attrDef.Key)
+		if attrName == "" {
+			diags = append(diags, invalidType(attrDef.Key.Range(),
+				"Object constructor map keys must be attribute names.")...)
+			continue
+		}
+
+		valExpr := attrDef.Value
+		var defaultExpr hcl.Expression
+		if call, callDiags := hcl.ExprCall(valExpr); !callDiags.HasErrors() && call.Name == "optional" {
+			optDiags := checkOptional(call, opts)
+			diags = append(diags, optDiags...)
+			if len(call.Arguments) == 0 {
+				atys[attrName] = cty.DynamicPseudoType
+				continue
+			}
+			if !optDiags.HasErrors() {
+				optional = append(optional, attrName)
+			}
+			valExpr = call.Arguments[0]
+			if len(call.Arguments) == 2 && !optDiags.HasErrors() {
+				defaultExpr = call.Arguments[1]
+			}
+		}
+
+		aty, attrDiags := getType(valExpr, opts)
+		diags = append(diags, attrDiags...)
+		atys[attrName] = aty
+
+		if defaultExpr != nil && !attrDiags.HasErrors() {
+			diags = append(diags, checkOptionalDefault(defaultExpr, aty)...)
+		}
+	}
+
+	if len(optional) == 0 {
+		return cty.Object(atys), diags
+	}
+
+	sort.Strings(optional)
+	return cty.ObjectWithOptionalAttrs(atys, optional), diags
+}
+
+func checkOptional(call *hcl.StaticCall, opts Options) hcl.Diagnostics {
+	if !opts.AllowOptionalAttrs {
+		detail := opts.optionalAttrsErr
+		if detail == "" {
+			detail = "Optional object attributes are not supported."
+		}
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  invalidTypeSummary,
+			Detail:   detail,
+			Subject:  call.NameRange.Ptr(),
+		}}
+	}
+
+	maxArgs := 1
+	detail := "The optional type modifier requires exactly one argument specifying the attribute type."
+	if opts.AllowOptionalDefaults {
+		maxArgs = 2
+		detail = "The optional type modifier requires the attribute type " +
+			"and optionally a default value as arguments."
+	}
+
+	if len(call.Arguments) == 0 || len(call.Arguments) > maxArgs {
+		subjectRange := call.ArgsRange
+		if len(call.Arguments) > maxArgs {
+			subjectRange = hcl.RangeBetween(call.Arguments[maxArgs].Range(),
+				call.Arguments[len(call.Arguments)-1].Range())
+		}
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  invalidTypeSummary,
+			Detail:   detail,
+			Subject:  &subjectRange,
+			Context:  call.ArgsRange.Ptr(),
+		}}
+	}
+
+	return nil
+}
+
+// checkOptionalDefault checks that the default value of an optional
+// attribute is known without any context and conforms to its type
+func checkOptionalDefault(expr hcl.Expression, ty cty.Type) hcl.Diagnostics {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for optional attribute",
+			Detail:   "The default value of an optional attribute must be a static value, without any references or function calls.",
+			Subject:  expr.Range().Ptr(),
+		}}
+	}
+
+	_, err := convert.Convert(val, ty)
+	if err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for optional attribute",
+			Detail:   fmt.Sprintf("This default value is not compatible with the attribute's type constraint: %s.", err),
+			Subject:  expr.Range().Ptr(),
+		}}
+	}
+
+	return nil
+}
+
+func invalidType(rng hcl.Range, detail string) hcl.Diagnostics {
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  invalidTypeSummary,
+		Detail:   detail,
+		Subject:  rng.Ptr(),
+	}}
+}
@@ -0,0 +1,308 @@
+package typeexpr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTypeConstraint(t *testing.T) {
+	testCases := []struct {
+		src          string
+		expectedType cty.Type
+	}{
+		{"string", cty.String},
+		{"number", cty.Number},
+		{"bool", cty.Bool},
+		{"any", cty.DynamicPseudoType},
+		{"list(string)", cty.List(cty.String)},
+		{"set(number)", cty.Set(cty.Number)},
+		{"map(any)", cty.Map(cty.DynamicPseudoType)},
+		{"tuple([string, number])", cty.Tuple([]cty.Type{cty.String, cty.Number})},
+		{
+			"object({ name = string, tags = map(string) })",
+			cty.Object(map[string]cty.Type{
+				"name": cty.String,
+				"tags": cty.Map(cty.String),
+			}),
+		},
+		{
+			"list(object({ name = string, port = optional(number) }))",
+			cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name": cty.String,
+				"port": cty.Number,
+			}, []string{"port"})),
+		},
+		{
+			`object({ port = optional(number, 80) })`,
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"port": cty.Number,
+			}, []string{"port"}),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.src), func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			ty, diags := TypeConstraint(expr, OptionsForVersion(nil, nil))
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+			if !ty.Equals(tc.expectedType) {
+				t.Fatalf("type mismatch.\nexpected: %#v\ngiven: %#v", tc.expectedType, ty)
+			}
+		})
+	}
+}
+
+func TestTypeConstraint_optionalAttrsByVersion(t *testing.T) {
+	optionalType := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"port": cty.Number,
+	}, []string{"port"})
+	requiredType := cty.Object(map[string]cty.Type{
+		"port": cty.Number,
+	})
+
+	testCases := []struct {
+		src             string
+		version         string
+		experiments     map[string]bool
+		expectedType    cty.Type
+		expectedSummary string
+	}{
+		{
+			"object({ port = optional(number) })",
+			"0.13.5",
+			map[string]bool{},
+			requiredType,
+			"Optional object attributes are not supported in Terraform 0.13.5. They require Terraform 0.14 or later.",
+		},
+		{
+			"object({ port = optional(number) })",
+			"0.14.0",
+			map[string]bool{},
+			requiredType,
+			"Optional object attributes are experimental in Terraform 0.14.0 and must be enabled " +
+				"via experiments = [module_variable_optional_attrs] in the terraform block.",
+		},
+		{
+			"object({ port = optional(number) })",
+			"1.2.0",
+			map[string]bool{OptionalAttrsExperiment: true},
+			optionalType,
+			"",
+		},
+		{
+			"object({ port = optional(number, 80) })",
+			"1.2.0",
+			map[string]bool{OptionalAttrsExperiment: true},
+			optionalType,
+			"The optional type modifier requires exactly one argument specifying the attribute type.",
+		},
+		{
+			"object({ port = optional(number, 80) })",
+			"1.3.0",
+			map[string]bool{},
+			optionalType,
+			"",
+		},
+		{
+			`object({ port = optional(number, "eighty") })`,
+			"1.3.0",
+			map[string]bool{},
+			optionalType,
+			"This default value is not compatible with the attribute's type constraint: a number is required.",
+		},
+		{
+			"object({ port = optional(number, var.port) })",
+			"1.3.0",
+			map[string]bool{},
+			optionalType,
+			"The default value of an optional attribute must be a static value, without any references or function calls.",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.version), func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			opts := OptionsForVersion(version.Must(version.NewVersion(tc.version)), tc.experiments)
+			ty, diags := TypeConstraint(expr, opts)
+
+			detail := ""
+			if len(diags) > 0 {
+				detail = diags[0].Detail
+			}
+			if detail != tc.expectedSummary {
+				t.Fatalf("diagnostic mismatch.\nexpected: %q\ngiven: %q", tc.expectedSummary, detail)
+			}
+			if tc.expectedSummary == "" && !ty.Equals(tc.expectedType) {
+				t.Fatalf("type mismatch.\nexpected: %#v\ngiven: %#v", tc.expectedType, ty)
+			}
+		})
+	}
+}
+
+func TestTypeConstraint_quotedByVersion(t *testing.T) {
+	testCases := []struct {
+		src              string
+		version          string
+		expectedType     cty.Type
+		expectedSeverity hcl.DiagnosticSeverity
+		expectedSummary  string
+	}{
+		{
+			`"string"`,
+			"0.12.0",
+			cty.String,
+			hcl.DiagWarning,
+			"Quoted type constraints are deprecated",
+		},
+		{
+			`"list"`,
+			"0.14.9",
+			cty.List(cty.DynamicPseudoType),
+			hcl.DiagWarning,
+			"Quoted type constraints are deprecated",
+		},
+		{
+			`"map"`,
+			"0.12.0",
+			cty.Map(cty.DynamicPseudoType),
+			hcl.DiagWarning,
+			"Quoted type constraints are deprecated",
+		},
+		{
+			`"map"`,
+			"0.15.0",
+			cty.Map(cty.DynamicPseudoType),
+			hcl.DiagError,
+			"Invalid quoted type constraints",
+		},
+		{
+			`"string"`,
+			"1.3.0",
+			cty.String,
+			hcl.DiagError,
+			"Invalid quoted type constraints",
+		},
+		{
+			`"number"`,
+			"0.12.0",
+			cty.DynamicPseudoType,
+			hcl.DiagError,
+			"Invalid legacy variable type hint",
+		},
+		{
+			`"list of ${foo}"`,
+			"0.12.0",
+			cty.DynamicPseudoType,
+			hcl.DiagError,
+			"Invalid legacy variable type hint",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s-%s", i, tc.src, tc.version), func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			opts := OptionsForVersion(version.Must(version.NewVersion(tc.version)), nil)
+			ty, diags := TypeConstraint(expr, opts)
+			if len(diags) != 1 {
+				t.Fatalf("expected exactly 1 diagnostic, given: %#v", diags)
+			}
+			if diags[0].Severity != tc.expectedSeverity {
+				t.Fatalf("severity mismatch.\nexpected: %#v\ngiven: %#v", tc.expectedSeverity, diags[0].Severity)
+			}
+			if diags[0].Summary != tc.expectedSummary {
+				t.Fatalf("summary mismatch.\nexpected: %q\ngiven: %q", tc.expectedSummary, diags[0].Summary)
+			}
+			if !ty.Equals(tc.expectedType) {
+				t.Fatalf("type mismatch.\nexpected: %#v\ngiven: %#v", tc.expectedType, ty)
+			}
+		})
+	}
+}
+
+func TestTypeConstraint_invalid(t *testing.T) {
+	testCases := []struct {
+		src           string
+		expectedDiags hcl.Diagnostics
+	}{
+		{
+			"list",
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  invalidTypeSummary,
+					Detail:   "The list type constructor requires one argument specifying the element type.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 5, Byte: 4},
+					},
+				},
+			},
+		},
+		{
+			"map(strin)",
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  invalidTypeSummary,
+					Detail:   `The keyword "strin" is not a valid type specification.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+						End:      hcl.Pos{Line: 1, Column: 10, Byte: 9},
+					},
+				},
+			},
+		},
+		{
+			"optional(string)",
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  invalidTypeSummary,
+					Detail: "The optional type modifier is only valid for object attribute types, " +
+						"e.g. object({ name = optional(string) }).",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.src), func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			_, diags = TypeConstraint(expr, OptionsForVersion(nil, nil))
+			if diff := cmp.Diff(tc.expectedDiags, diags); diff != "" {
+				t.Fatalf("diagnostics mismatch: %s", diff)
+			}
+		})
+	}
+}
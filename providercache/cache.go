@@ -0,0 +1,334 @@
+// Package providercache implements an on-disk cache of provider schemas
+// keyed by provider address and version, such that schemas don't need
+// to be obtained from Terraform (or the provider itself) repeatedly.
+package providercache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+const (
+	entryFormatVersion = "1"
+	entryExtension     = ".json"
+	hashPrefix         = "sha256:"
+)
+
+// Cache stores provider schemas in a directory
+// using the layout of HOSTNAME/NAMESPACE/TYPE/VERSION.json
+type Cache struct {
+	dir        string
+	maxEntries int
+}
+
+// entry represents a single cached provider schema
+type entry struct {
+	FormatVersion string          `json:"format_version"`
+	Address       string          `json:"address"`
+	Version       string          `json:"version"`
+	Hash          string          `json:"hash"`
+	Schema        json.RawMessage `json:"schema"`
+}
+
+// NewCache creates a new cache in the given directory,
+// creating the directory if it doesn't exist
+func NewCache(dir string) (*Cache, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// SetMaxEntries sets the maximum number of schemas kept in the cache.
+// Least recently used schemas are evicted when storing new ones
+// beyond that number. Zero (default) means no limit.
+func (c *Cache) SetMaxEntries(n int) {
+	c.maxEntries = n
+}
+
+// Put stores the schema of the given provider (source address
+// such as hashicorp/aws) in the given version
+func (c *Cache) Put(addr string, v *version.Version, ps *tfjson.ProviderSchema) error {
+	path, srcAddr, err := c.entryPath(addr, v)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(ps)
+	if err != nil {
+		return err
+	}
+
+	e := &entry{
+		FormatVersion: entryFormatVersion,
+		Address:       srcAddr.String(),
+		Version:       v.String(),
+		Hash:          hashBytes(b),
+		Schema:        b,
+	}
+	eb, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// write into temporary file first to avoid
+	// concurrent readers seeing a partially written entry
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmpFile.Write(eb)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	err = os.Rename(tmpFile.Name(), path)
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+
+	if c.maxEntries > 0 {
+		return c.evictLeastRecentlyUsed(c.maxEntries)
+	}
+
+	return nil
+}
+
+// PutAll stores all given provider schemas (as produced by
+// `terraform providers schema -json`) using the given versions.
+//
+// Addresses are compared in their normalized form, such that
+// e.g. hashicorp/aws matches registry.terraform.io/hashicorp/aws.
+// Providers with unknown version are skipped. Schemas of all matched
+// providers are stored before an error is returned for any versions
+// of providers which have no schema.
+func (c *Cache) PutAll(ps *tfjson.ProviderSchemas, versions map[string]*version.Version) error {
+	schemas := make(map[addrs.Provider]*tfjson.ProviderSchema, 0)
+	if ps != nil {
+		for addr, pSchema := range ps.Schemas {
+			srcAddr, err := addrs.ParseProviderSourceString(addr)
+			if err != nil {
+				return err
+			}
+			schemas[srcAddr] = pSchema
+		}
+	}
+
+	unmatched := make([]string, 0)
+	for addr, v := range versions {
+		srcAddr, err := addrs.ParseProviderSourceString(addr)
+		if err != nil {
+			return err
+		}
+		pSchema, ok := schemas[srcAddr]
+		if !ok {
+			unmatched = append(unmatched, addr)
+			continue
+		}
+		err = c.Put(addr, v, pSchema)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		return fmt.Errorf("no schemas found for providers: %s", strings.Join(unmatched, ", "))
+	}
+
+	return nil
+}
+
+// LoadProviderSchema returns the cached schema of the given provider
+// and whether it was found in the cache.
+//
+// Entries which fail the integrity check are removed from the cache
+// and reported as an error.
+func (c *Cache) LoadProviderSchema(addr string, v *version.Version) (*tfjson.ProviderSchema, bool, error) {
+	path, _, err := c.entryPath(addr, v)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var e entry
+	err = json.Unmarshal(b, &e)
+	if err != nil {
+		os.Remove(path)
+		return nil, false, &CorruptedEntryError{Path: path, Err: err}
+	}
+	if e.FormatVersion != entryFormatVersion {
+		// written by incompatible version of this library
+		os.Remove(path)
+		return nil, false, nil
+	}
+	if hashBytes(e.Schema) != e.Hash {
+		os.Remove(path)
+		return nil, false, &CorruptedEntryError{
+			Path: path,
+			Err:  fmt.Errorf("hash mismatch (expected %s)", e.Hash),
+		}
+	}
+
+	var ps tfjson.ProviderSchema
+	err = json.Unmarshal(e.Schema, &ps)
+	if err != nil {
+		os.Remove(path)
+		return nil, false, &CorruptedEntryError{Path: path, Err: err}
+	}
+
+	// track last use for eviction
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return &ps, true, nil
+}
+
+// Remove removes the schema of the given provider from the cache
+func (c *Cache) Remove(addr string, v *version.Version) error {
+	path, _, err := c.entryPath(addr, v)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// EvictOlderThan removes any schemas which were not used
+// (stored or loaded) within the given duration
+func (c *Cache) EvictOlderThan(d time.Duration) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Now().Add(-d)
+	for _, e := range entries {
+		if e.lastUsed.Before(threshold) {
+			err := os.Remove(e.path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Cache) evictLeastRecentlyUsed(maxEntries int) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) <= maxEntries {
+		return nil
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].lastUsed.After(entries[j].lastUsed)
+	})
+
+	for _, e := range entries[maxEntries:] {
+		err := os.Remove(e.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+type entryInfo struct {
+	path     string
+	lastUsed time.Time
+}
+
+func (c *Cache) entries() ([]entryInfo, error) {
+	matches, err := filepath.Glob(filepath.Join(c.dir, "*", "*", "*", "*"+entryExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]entryInfo, 0, len(matches))
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		entries = append(entries, entryInfo{
+			path:     path,
+			lastUsed: info.ModTime(),
+		})
+	}
+	return entries, nil
+}
+
+func (c *Cache) entryPath(addr string, v *version.Version) (string, addrs.Provider, error) {
+	if v == nil {
+		return "", addrs.Provider{}, fmt.Errorf("version required for %s", addr)
+	}
+
+	srcAddr, err := addrs.ParseProviderSourceString(addr)
+	if err != nil {
+		return "", addrs.Provider{}, err
+	}
+
+	return filepath.Join(c.dir,
+		srcAddr.Hostname.String(),
+		srcAddr.Namespace,
+		srcAddr.Type,
+		v.String()+entryExtension), srcAddr, nil
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// CorruptedEntryError indicates that a cached schema failed
+// the integrity check (and was removed from the cache)
+type CorruptedEntryError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptedEntryError) Error() string {
+	return fmt.Sprintf("corrupted cache entry %s: %s", e.Path, e.Err)
+}
+
+func (e *CorruptedEntryError) Unwrap() error {
+	return e.Err
+}
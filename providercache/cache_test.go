@@ -0,0 +1,248 @@
+package providercache
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCache_putAndLoad(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	v := version.Must(version.NewVersion("3.12.0"))
+	err := c.Put("hashicorp/aws", v, testSchema("aws_instance"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPath := filepath.Join(dir, "registry.terraform.io", "hashicorp", "aws", "3.12.0.json")
+	if _, err := os.Stat(expectedPath); err != nil {
+		t.Fatalf("expected entry at %s: %s", expectedPath, err)
+	}
+
+	// address is normalized
+	ps, ok, err := c.LoadProviderSchema("registry.terraform.io/hashicorp/aws", v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected schema to be found")
+	}
+	if diff := cmp.Diff(testSchema("aws_instance"), ps, cmpopts.IgnoreUnexported(cty.Type{})); diff != "" {
+		t.Fatalf("schema mismatch: %s", diff)
+	}
+
+	_, ok, err = c.LoadProviderSchema("hashicorp/aws", version.Must(version.NewVersion("3.13.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected no schema for different version")
+	}
+}
+
+func TestCache_corruptedEntry(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	v := version.Must(version.NewVersion("1.0.0"))
+	err := c.Put("hashicorp/random", v, testSchema("random_id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "registry.terraform.io", "hashicorp", "random", "1.0.0.json")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = []byte(strings.Replace(string(b), "random_id", "random_xx", 1))
+	err = ioutil.WriteFile(path, b, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok, err := c.LoadProviderSchema("hashicorp/random", v)
+	if err == nil {
+		t.Fatal("expected integrity error")
+	}
+	var ceErr *CorruptedEntryError
+	if !errors.As(err, &ceErr) {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if ok {
+		t.Fatal("expected corrupted schema not to be returned")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected corrupted entry to be removed, given: %v", err)
+	}
+}
+
+func TestCache_maxEntries(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+	c.SetMaxEntries(2)
+
+	v := version.Must(version.NewVersion("1.0.0"))
+	for i, name := range []string{"aws", "google", "random"} {
+		err := c.Put("hashicorp/"+name, v, testSchema(name+"_thing"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// make access times distinct
+		setLastUsed(t, dir, name, time.Now().Add(time.Duration(i-10)*time.Minute))
+	}
+
+	// aws is the least recently used
+	expectedPresent := map[string]bool{
+		"aws":    false,
+		"google": true,
+		"random": true,
+	}
+	for name, present := range expectedPresent {
+		_, ok, err := c.LoadProviderSchema("hashicorp/"+name, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != present {
+			t.Fatalf("%s: expected present: %t, given: %t", name, present, ok)
+		}
+	}
+}
+
+func TestCache_evictOlderThan(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	v := version.Must(version.NewVersion("1.0.0"))
+	for _, name := range []string{"aws", "google"} {
+		err := c.Put("hashicorp/"+name, v, testSchema(name+"_thing"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	setLastUsed(t, dir, "aws", time.Now().Add(-48*time.Hour))
+
+	err := c.EvictOlderThan(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok, _ := c.LoadProviderSchema("hashicorp/aws", v)
+	if ok {
+		t.Fatal("expected aws schema to be evicted")
+	}
+	_, ok, _ = c.LoadProviderSchema("hashicorp/google", v)
+	if !ok {
+		t.Fatal("expected google schema to be kept")
+	}
+}
+
+func TestCache_putAll(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws":    testSchema("aws_instance"),
+			"registry.terraform.io/hashicorp/google": testSchema("google_compute_instance"),
+		},
+	}
+	v := version.Must(version.NewVersion("1.0.0"))
+	err := c.PutAll(ps, map[string]*version.Version{
+		"hashicorp/aws": v,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok, _ := c.LoadProviderSchema("hashicorp/aws", v)
+	if !ok {
+		t.Fatal("expected aws schema to be cached")
+	}
+	_, ok, _ = c.LoadProviderSchema("hashicorp/google", v)
+	if ok {
+		t.Fatal("expected google schema (of unknown version) not to be cached")
+	}
+}
+
+func TestCache_putAllUnmatched(t *testing.T) {
+	c, dir := newTestCache(t)
+	defer os.RemoveAll(dir)
+
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": testSchema("aws_instance"),
+		},
+	}
+	v := version.Must(version.NewVersion("1.0.0"))
+	err := c.PutAll(ps, map[string]*version.Version{
+		"hashicorp/aws":    v,
+		"hashicorp/google": v,
+	})
+	if err == nil {
+		t.Fatal("expected error for google provider without schema")
+	}
+	expectedErr := "no schemas found for providers: hashicorp/google"
+	if err.Error() != expectedErr {
+		t.Fatalf("error mismatch.\nexpected: %q\ngiven: %q", expectedErr, err.Error())
+	}
+
+	_, ok, _ := c.LoadProviderSchema("hashicorp/aws", v)
+	if !ok {
+		t.Fatal("expected aws schema to be cached")
+	}
+}
+
+func newTestCache(t *testing.T) (*Cache, string) {
+	dir, err := ioutil.TempDir("", "schema-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, dir
+}
+
+func setLastUsed(t *testing.T, dir, providerType string, tm time.Time) {
+	path := filepath.Join(dir, "registry.terraform.io", "hashicorp", providerType, "1.0.0.json")
+	err := os.Chtimes(path, tm, tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testSchema(resourceType string) *tfjson.ProviderSchema {
+	return &tfjson.ProviderSchema{
+		ConfigSchema: &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"region": {AttributeType: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*tfjson.Schema{
+			resourceType: {
+				Block: &tfjson.SchemaBlock{
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"name": {AttributeType: cty.String, Required: true},
+					},
+				},
+			},
+		},
+	}
+}
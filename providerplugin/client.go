@@ -0,0 +1,204 @@
+package providerplugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin5"
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin6"
+	"google.golang.org/grpc"
+)
+
+const (
+	// magicCookieKey and magicCookieValue are used by Terraform
+	// to signal to a provider that it is being launched as a plugin
+	magicCookieKey   = "TF_PLUGIN_MAGIC_COOKIE"
+	magicCookieValue = "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2"
+
+	coreProtocolVersion = 1
+)
+
+// defaultStartTimeout is the maximum time to wait for the provider
+// to complete the handshake after being launched, unless overridden
+// via WithStartTimeout
+const defaultStartTimeout = 1 * time.Minute
+
+type clientOptions struct {
+	startTimeout time.Duration
+}
+
+// ClientOption configures how providers are launched
+// when obtaining their schemas
+type ClientOption func(*clientOptions)
+
+// WithStartTimeout sets the maximum time to wait for the provider
+// to complete the handshake and accept the connection after being launched
+func WithStartTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.startTimeout = timeout
+	}
+}
+
+func newClientOptions(opts []ClientOption) *clientOptions {
+	o := &clientOptions{
+		startTimeout: defaultStartTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ProviderSchema launches the provider, obtains its schema
+// via GetProviderSchema (tfplugin5 or tfplugin6) and stops it.
+func (p *InstalledProvider) ProviderSchema(ctx context.Context, opts ...ClientOption) (*tfjson.ProviderSchema, error) {
+	o := newClientOptions(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Env = append(os.Environ(),
+		magicCookieKey+"="+magicCookieValue,
+		"PLUGIN_PROTOCOL_VERSIONS=5,6",
+		"PLUGIN_MIN_PORT=10000",
+		"PLUGIN_MAX_PORT=25000",
+	)
+	cmd.Stderr = ioutil.Discard
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch %s: %w", p.Address, err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	hs, err := readHandshake(stdout, o.startTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Address, err)
+	}
+
+	if hs.ProtocolVersion != 5 && hs.ProtocolVersion != 6 {
+		return nil, fmt.Errorf("%s: unsupported plugin protocol version %d",
+			p.Address, hs.ProtocolVersion)
+	}
+
+	dialCtx, dialCancel := context.WithTimeout(ctx, o.startTimeout)
+	defer dialCancel()
+
+	conn, err := grpc.DialContext(dialCtx, hs.Address,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, hs.Network, addr)
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to connect: %w", p.Address, err)
+	}
+	defer conn.Close()
+
+	var ps *tfjson.ProviderSchema
+	if hs.ProtocolVersion == 6 {
+		resp := &tfplugin6.GetProviderSchema_Response{}
+		err = conn.Invoke(ctx, "/tfplugin6.Provider/GetProviderSchema",
+			&tfplugin6.GetProviderSchema_Request{}, resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to obtain schema: %w", p.Address, err)
+		}
+		ps, err = providerSchemaFromProto6(resp)
+	} else {
+		resp := &tfplugin5.GetProviderSchema_Response{}
+		err = conn.Invoke(ctx, "/tfplugin5.Provider/GetSchema",
+			&tfplugin5.GetProviderSchema_Request{}, resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to obtain schema: %w", p.Address, err)
+		}
+		ps, err = providerSchemaFromProto5(resp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Address, err)
+	}
+
+	return ps, nil
+}
+
+type handshake struct {
+	ProtocolVersion int
+	Network         string
+	Address         string
+}
+
+// readHandshake reads the go-plugin handshake line,
+// i.e. CORE-PROTOCOL-VERSION|APP-PROTOCOL-VERSION|NETWORK|ADDR|PROTOCOL
+func readHandshake(r io.Reader, timeout time.Duration) (*handshake, error) {
+	lineCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	go func() {
+		br := bufio.NewReader(r)
+		line, err := br.ReadString('\n')
+		if err != nil {
+			errCh <- fmt.Errorf("failed to read handshake: %w", err)
+			return
+		}
+		lineCh <- line
+		// drain any further output to avoid blocking the plugin
+		io.Copy(ioutil.Discard, br)
+	}()
+
+	var line string
+	select {
+	case line = <-lineCh:
+	case err := <-errCh:
+		return nil, err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timeout waiting for handshake")
+	}
+
+	return parseHandshake(line)
+}
+
+func parseHandshake(line string) (*handshake, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("unrecognized handshake: %q", line)
+	}
+
+	coreVersion, err := strconv.Atoi(parts[0])
+	if err != nil || coreVersion != coreProtocolVersion {
+		return nil, fmt.Errorf("incompatible core plugin protocol version: %q", parts[0])
+	}
+
+	protoVersion, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin protocol version: %q", parts[1])
+	}
+
+	if len(parts) < 5 || parts[4] != "grpc" {
+		return nil, fmt.Errorf("unsupported plugin protocol (only grpc is supported)")
+	}
+
+	return &handshake{
+		ProtocolVersion: protoVersion,
+		Network:         parts[2],
+		Address:         parts[3],
+	}, nil
+}
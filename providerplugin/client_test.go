@@ -0,0 +1,319 @@
+package providerplugin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-multierror"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLoadProviderSchemas_protocol5(t *testing.T) {
+	dir := buildFakeProvider(t, 5)
+	defer os.RemoveAll(dir)
+
+	ps, err := LoadProviderSchemas(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchemas := &tfjson.ProviderSchemas{
+		FormatVersion: tfjson.ProviderSchemasFormatVersion,
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/fake": expectedFakeProviderSchema(nil),
+		},
+	}
+	if diff := cmp.Diff(expectedSchemas, ps, cmpopts.IgnoreUnexported(cty.Type{})); diff != "" {
+		t.Fatalf("schema mismatch: %s", diff)
+	}
+}
+
+func TestLoadProviderSchemas_protocol6(t *testing.T) {
+	dir := buildFakeProvider(t, 6)
+	defer os.RemoveAll(dir)
+
+	ps, err := LoadProviderSchemas(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchemas := &tfjson.ProviderSchemas{
+		FormatVersion: tfjson.ProviderSchemasFormatVersion,
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/fake": expectedFakeProviderSchema(map[string]*tfjson.SchemaAttribute{
+				"settings": {
+					AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"key": {
+								AttributeType:   cty.String,
+								Required:        true,
+								DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+							},
+						},
+						NestingMode: tfjson.SchemaNestingModeList,
+						MinItems:    1,
+					},
+					Optional:        true,
+					DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+				},
+			}),
+		},
+	}
+	if diff := cmp.Diff(expectedSchemas, ps, cmpopts.IgnoreUnexported(cty.Type{})); diff != "" {
+		t.Fatalf("schema mismatch: %s", diff)
+	}
+}
+
+func TestLoadProviderSchemas_diagnostics(t *testing.T) {
+	dir := buildFakeProvider(t, 5)
+	defer os.RemoveAll(dir)
+
+	os.Setenv("FAKE_PROVIDER_ERROR", "invalid schema")
+	defer os.Unsetenv("FAKE_PROVIDER_ERROR")
+
+	ps, err := LoadProviderSchemas(context.Background(), dir)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	merr, ok := err.(*multierror.Error)
+	if !ok {
+		t.Fatalf("expected *multierror.Error, given: %#v", err)
+	}
+	if len(merr.Errors) != 1 {
+		t.Fatalf("expected exactly 1 error, given: %#v", merr.Errors)
+	}
+	expectedErr := "registry.terraform.io/hashicorp/fake: provider returned errors: invalid schema: something went wrong"
+	if merr.Errors[0].Error() != expectedErr {
+		t.Fatalf("error mismatch.\nexpected: %q\ngiven: %q", expectedErr, merr.Errors[0].Error())
+	}
+
+	if len(ps.Schemas) != 0 {
+		t.Fatalf("expected no schemas, given: %#v", ps.Schemas)
+	}
+}
+
+func TestLoadProviderSchemas_partial(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts cannot be executed on Windows")
+	}
+
+	dir := buildFakeProvider(t, 5)
+	defer os.RemoveAll(dir)
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	brokenProviders := []string{
+		// older version of a working provider
+		"registry.terraform.io/hashicorp/fake/0.9.0/" + platform + "/terraform-provider-fake_v0.9.0",
+		"example.com/acme/broken/1.0.0/" + platform + "/terraform-provider-broken_v1.0.0",
+	}
+	for _, path := range brokenProviders {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		err := os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(fullPath, []byte("#!/bin/sh\nexit 1\n"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ps, err := LoadProviderSchemas(context.Background(), dir)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	merr, ok := err.(*multierror.Error)
+	if !ok {
+		t.Fatalf("expected *multierror.Error, given: %#v", err)
+	}
+	if len(merr.Errors) != 1 {
+		t.Fatalf("expected exactly 1 error, given: %#v", merr.Errors)
+	}
+	if !strings.HasPrefix(merr.Errors[0].Error(), "example.com/acme/broken: ") {
+		t.Fatalf("expected error of broken provider, given: %q", merr.Errors[0].Error())
+	}
+
+	expectedSchemas := &tfjson.ProviderSchemas{
+		FormatVersion: tfjson.ProviderSchemasFormatVersion,
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/fake": expectedFakeProviderSchema(nil),
+		},
+	}
+	if diff := cmp.Diff(expectedSchemas, ps, cmpopts.IgnoreUnexported(cty.Type{})); diff != "" {
+		t.Fatalf("schema mismatch: %s", diff)
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	testCases := []struct {
+		line              string
+		expectedHandshake *handshake
+		expectedErr       bool
+	}{
+		{
+			"1|5|tcp|127.0.0.1:1234|grpc\n",
+			&handshake{
+				ProtocolVersion: 5,
+				Network:         "tcp",
+				Address:         "127.0.0.1:1234",
+			},
+			false,
+		},
+		{
+			"1|6|unix|/tmp/plugin123|grpc\n",
+			&handshake{
+				ProtocolVersion: 6,
+				Network:         "unix",
+				Address:         "/tmp/plugin123",
+			},
+			false,
+		},
+		{
+			"1|4|tcp|127.0.0.1:1234\n",
+			nil,
+			true,
+		},
+		{
+			"2|5|tcp|127.0.0.1:1234|grpc\n",
+			nil,
+			true,
+		},
+		{
+			"This binary is a plugin.\n",
+			nil,
+			true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.line), func(t *testing.T) {
+			hs, err := parseHandshake(tc.line)
+			if err != nil {
+				if !tc.expectedErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if tc.expectedErr {
+				t.Fatal("expected error")
+			}
+			if diff := cmp.Diff(tc.expectedHandshake, hs); diff != "" {
+				t.Fatalf("handshake mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func expectedFakeProviderSchema(extraAttrs map[string]*tfjson.SchemaAttribute) *tfjson.ProviderSchema {
+	resourceAttrs := map[string]*tfjson.SchemaAttribute{
+		"name": {
+			AttributeType:   cty.String,
+			Required:        true,
+			DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+		},
+		"password": {
+			AttributeType:   cty.String,
+			Optional:        true,
+			Sensitive:       true,
+			DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+		},
+	}
+	for name, attr := range extraAttrs {
+		resourceAttrs[name] = attr
+	}
+
+	return &tfjson.ProviderSchema{
+		ConfigSchema: &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"region": {
+						AttributeType:   cty.String,
+						Description:     "Region to use",
+						Optional:        true,
+						DescriptionKind: tfjson.SchemaDescriptionKindMarkdown,
+					},
+				},
+				DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+			},
+		},
+		ResourceSchemas: map[string]*tfjson.Schema{
+			"fake_instance": {
+				Version: 2,
+				Block: &tfjson.SchemaBlock{
+					Attributes: resourceAttrs,
+					NestedBlocks: map[string]*tfjson.SchemaBlockType{
+						"timeouts": {
+							NestingMode: tfjson.SchemaNestingModeSingle,
+							Block: &tfjson.SchemaBlock{
+								Attributes: map[string]*tfjson.SchemaAttribute{
+									"create": {
+										AttributeType:   cty.String,
+										Optional:        true,
+										DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+									},
+								},
+								DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+							},
+						},
+					},
+					Description:     "Fake instance",
+					DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*tfjson.Schema{
+			"fake_image": {
+				Block: &tfjson.SchemaBlock{
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"id": {
+							AttributeType:   cty.String,
+							Computed:        true,
+							DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+						},
+					},
+					Deprecated:      true,
+					DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+				},
+			},
+		},
+	}
+}
+
+// buildFakeProvider builds the fake provider from testdata
+// into a new plugin cache directory and returns path to it
+func buildFakeProvider(t *testing.T, protocolVersion int) string {
+	dir, err := ioutil.TempDir("", "plugin-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binName := "terraform-provider-fake_v1.0.0"
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	binPath := filepath.Join(dir, "registry.terraform.io", "hashicorp", "fake",
+		"1.0.0", runtime.GOOS+"_"+runtime.GOARCH, binName)
+
+	cmd := exec.Command("go", "build",
+		"-ldflags", fmt.Sprintf("-X main.protocolVersion=%d", protocolVersion),
+		"-o", binPath, "./testdata/fake-provider")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to build fake provider: %s\n%s", err, out)
+	}
+
+	return dir
+}
@@ -0,0 +1,311 @@
+package providerplugin
+
+import (
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin5"
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin6"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// The conversion below mirrors what Terraform does when producing
+// `terraform providers schema -json` from GetProviderSchema responses.
+//
+// Types in internal/ are generated from tfplugin5.proto and tfplugin6.proto,
+// which only differ (for the purposes of schemas) in tfplugin6
+// supporting nested attribute types.
+
+var blockNestingModes5 = map[tfplugin5.Schema_NestedBlock_NestingMode]tfjson.SchemaNestingMode{
+	tfplugin5.Schema_NestedBlock_SINGLE: tfjson.SchemaNestingModeSingle,
+	tfplugin5.Schema_NestedBlock_LIST:   tfjson.SchemaNestingModeList,
+	tfplugin5.Schema_NestedBlock_SET:    tfjson.SchemaNestingModeSet,
+	tfplugin5.Schema_NestedBlock_MAP:    tfjson.SchemaNestingModeMap,
+	tfplugin5.Schema_NestedBlock_GROUP:  tfjson.SchemaNestingModeGroup,
+}
+
+var descriptionKinds5 = map[tfplugin5.StringKind]tfjson.SchemaDescriptionKind{
+	tfplugin5.StringKind_PLAIN:    tfjson.SchemaDescriptionKindPlain,
+	tfplugin5.StringKind_MARKDOWN: tfjson.SchemaDescriptionKindMarkdown,
+}
+
+func providerSchemaFromProto5(resp *tfplugin5.GetProviderSchema_Response) (*tfjson.ProviderSchema, error) {
+	var diagErrs []string
+	for _, diag := range resp.Diagnostics {
+		if diag.Severity == tfplugin5.Diagnostic_ERROR {
+			diagErrs = append(diagErrs, diagnosticMessage(diag.Summary, diag.Detail))
+		}
+	}
+	if len(diagErrs) > 0 {
+		return nil, fmt.Errorf("provider returned errors: %s", strings.Join(diagErrs, "; "))
+	}
+
+	ps := &tfjson.ProviderSchema{
+		ResourceSchemas:   make(map[string]*tfjson.Schema, 0),
+		DataSourceSchemas: make(map[string]*tfjson.Schema, 0),
+	}
+
+	if resp.Provider != nil {
+		schema, err := schemaFromProto5(resp.Provider)
+		if err != nil {
+			return nil, err
+		}
+		ps.ConfigSchema = schema
+	}
+
+	for name, rs := range resp.ResourceSchemas {
+		schema, err := schemaFromProto5(rs)
+		if err != nil {
+			return nil, fmt.Errorf("resource %q: %w", name, err)
+		}
+		ps.ResourceSchemas[name] = schema
+	}
+
+	for name, ds := range resp.DataSourceSchemas {
+		schema, err := schemaFromProto5(ds)
+		if err != nil {
+			return nil, fmt.Errorf("data source %q: %w", name, err)
+		}
+		ps.DataSourceSchemas[name] = schema
+	}
+
+	return ps, nil
+}
+
+func schemaFromProto5(s *tfplugin5.Schema) (*tfjson.Schema, error) {
+	block, err := blockFromProto5(s.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfjson.Schema{
+		Version: uint64(s.Version),
+		Block:   block,
+	}, nil
+}
+
+func blockFromProto5(b *tfplugin5.Schema_Block) (*tfjson.SchemaBlock, error) {
+	block := &tfjson.SchemaBlock{
+		DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+	}
+	if b == nil {
+		return block, nil
+	}
+
+	block.Description = b.Description
+	block.DescriptionKind = descriptionKinds5[b.DescriptionKind]
+	block.Deprecated = b.Deprecated
+
+	for _, a := range b.Attributes {
+		ty, err := attributeTypeFromProto(a.Name, a.Type)
+		if err != nil {
+			return nil, err
+		}
+		if block.Attributes == nil {
+			block.Attributes = make(map[string]*tfjson.SchemaAttribute, 0)
+		}
+		block.Attributes[a.Name] = &tfjson.SchemaAttribute{
+			AttributeType:   ty,
+			Description:     a.Description,
+			DescriptionKind: descriptionKinds5[a.DescriptionKind],
+			Deprecated:      a.Deprecated,
+			Required:        a.Required,
+			Optional:        a.Optional,
+			Computed:        a.Computed,
+			Sensitive:       a.Sensitive,
+		}
+	}
+
+	for _, nb := range b.BlockTypes {
+		nested, err := blockFromProto5(nb.Block)
+		if err != nil {
+			return nil, err
+		}
+		if block.NestedBlocks == nil {
+			block.NestedBlocks = make(map[string]*tfjson.SchemaBlockType, 0)
+		}
+		block.NestedBlocks[nb.TypeName] = &tfjson.SchemaBlockType{
+			NestingMode: blockNestingModes5[nb.Nesting],
+			Block:       nested,
+			MinItems:    uint64(nb.MinItems),
+			MaxItems:    uint64(nb.MaxItems),
+		}
+	}
+
+	return block, nil
+}
+
+var blockNestingModes6 = map[tfplugin6.Schema_NestedBlock_NestingMode]tfjson.SchemaNestingMode{
+	tfplugin6.Schema_NestedBlock_SINGLE: tfjson.SchemaNestingModeSingle,
+	tfplugin6.Schema_NestedBlock_LIST:   tfjson.SchemaNestingModeList,
+	tfplugin6.Schema_NestedBlock_SET:    tfjson.SchemaNestingModeSet,
+	tfplugin6.Schema_NestedBlock_MAP:    tfjson.SchemaNestingModeMap,
+	tfplugin6.Schema_NestedBlock_GROUP:  tfjson.SchemaNestingModeGroup,
+}
+
+var objectNestingModes6 = map[tfplugin6.Schema_Object_NestingMode]tfjson.SchemaNestingMode{
+	tfplugin6.Schema_Object_SINGLE: tfjson.SchemaNestingModeSingle,
+	tfplugin6.Schema_Object_LIST:   tfjson.SchemaNestingModeList,
+	tfplugin6.Schema_Object_SET:    tfjson.SchemaNestingModeSet,
+	tfplugin6.Schema_Object_MAP:    tfjson.SchemaNestingModeMap,
+}
+
+var descriptionKinds6 = map[tfplugin6.StringKind]tfjson.SchemaDescriptionKind{
+	tfplugin6.StringKind_PLAIN:    tfjson.SchemaDescriptionKindPlain,
+	tfplugin6.StringKind_MARKDOWN: tfjson.SchemaDescriptionKindMarkdown,
+}
+
+func providerSchemaFromProto6(resp *tfplugin6.GetProviderSchema_Response) (*tfjson.ProviderSchema, error) {
+	var diagErrs []string
+	for _, diag := range resp.Diagnostics {
+		if diag.Severity == tfplugin6.Diagnostic_ERROR {
+			diagErrs = append(diagErrs, diagnosticMessage(diag.Summary, diag.Detail))
+		}
+	}
+	if len(diagErrs) > 0 {
+		return nil, fmt.Errorf("provider returned errors: %s", strings.Join(diagErrs, "; "))
+	}
+
+	ps := &tfjson.ProviderSchema{
+		ResourceSchemas:   make(map[string]*tfjson.Schema, 0),
+		DataSourceSchemas: make(map[string]*tfjson.Schema, 0),
+	}
+
+	if resp.Provider != nil {
+		schema, err := schemaFromProto6(resp.Provider)
+		if err != nil {
+			return nil, err
+		}
+		ps.ConfigSchema = schema
+	}
+
+	for name, rs := range resp.ResourceSchemas {
+		schema, err := schemaFromProto6(rs)
+		if err != nil {
+			return nil, fmt.Errorf("resource %q: %w", name, err)
+		}
+		ps.ResourceSchemas[name] = schema
+	}
+
+	for name, ds := range resp.DataSourceSchemas {
+		schema, err := schemaFromProto6(ds)
+		if err != nil {
+			return nil, fmt.Errorf("data source %q: %w", name, err)
+		}
+		ps.DataSourceSchemas[name] = schema
+	}
+
+	return ps, nil
+}
+
+func schemaFromProto6(s *tfplugin6.Schema) (*tfjson.Schema, error) {
+	block, err := blockFromProto6(s.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfjson.Schema{
+		Version: uint64(s.Version),
+		Block:   block,
+	}, nil
+}
+
+func blockFromProto6(b *tfplugin6.Schema_Block) (*tfjson.SchemaBlock, error) {
+	block := &tfjson.SchemaBlock{
+		DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+	}
+	if b == nil {
+		return block, nil
+	}
+
+	block.Description = b.Description
+	block.DescriptionKind = descriptionKinds6[b.DescriptionKind]
+	block.Deprecated = b.Deprecated
+
+	if len(b.Attributes) > 0 {
+		attrs, err := attributesFromProto6(b.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		block.Attributes = attrs
+	}
+
+	for _, nb := range b.BlockTypes {
+		nested, err := blockFromProto6(nb.Block)
+		if err != nil {
+			return nil, err
+		}
+		if block.NestedBlocks == nil {
+			block.NestedBlocks = make(map[string]*tfjson.SchemaBlockType, 0)
+		}
+		block.NestedBlocks[nb.TypeName] = &tfjson.SchemaBlockType{
+			NestingMode: blockNestingModes6[nb.Nesting],
+			Block:       nested,
+			MinItems:    uint64(nb.MinItems),
+			MaxItems:    uint64(nb.MaxItems),
+		}
+	}
+
+	return block, nil
+}
+
+func attributesFromProto6(attributes []*tfplugin6.Schema_Attribute) (map[string]*tfjson.SchemaAttribute, error) {
+	attrs := make(map[string]*tfjson.SchemaAttribute, len(attributes))
+
+	for _, a := range attributes {
+		attr := &tfjson.SchemaAttribute{
+			Description:     a.Description,
+			DescriptionKind: descriptionKinds6[a.DescriptionKind],
+			Deprecated:      a.Deprecated,
+			Required:        a.Required,
+			Optional:        a.Optional,
+			Computed:        a.Computed,
+			Sensitive:       a.Sensitive,
+		}
+
+		if a.NestedType != nil {
+			nestedAttrs, err := attributesFromProto6(a.NestedType.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			attr.AttributeNestedType = &tfjson.SchemaNestedAttributeType{
+				Attributes:  nestedAttrs,
+				NestingMode: objectNestingModes6[a.NestedType.Nesting],
+				MinItems:    uint64(a.NestedType.MinItems),
+				MaxItems:    uint64(a.NestedType.MaxItems),
+			}
+		} else {
+			ty, err := attributeTypeFromProto(a.Name, a.Type)
+			if err != nil {
+				return nil, err
+			}
+			attr.AttributeType = ty
+		}
+
+		attrs[a.Name] = attr
+	}
+
+	return attrs, nil
+}
+
+// attributeTypeFromProto decodes the JSON-encoded cty type
+// which both protocol versions use for attribute types
+func attributeTypeFromProto(name string, b []byte) (cty.Type, error) {
+	if len(b) == 0 {
+		return cty.NilType, nil
+	}
+
+	var ty cty.Type
+	if err := ty.UnmarshalJSON(b); err != nil {
+		return cty.NilType, fmt.Errorf("invalid type of attribute %q: %w", name, err)
+	}
+	return ty, nil
+}
+
+func diagnosticMessage(summary, detail string) string {
+	if detail != "" {
+		return summary + ": " + detail
+	}
+	return summary
+}
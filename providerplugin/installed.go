@@ -0,0 +1,112 @@
+package providerplugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+)
+
+// InstalledProvider represents a single provider binary
+// found in a plugin cache directory
+type InstalledProvider struct {
+	// Address is the fully qualified provider address,
+	// e.g. registry.terraform.io/hashicorp/aws
+	Address string
+
+	Version *version.Version
+
+	// Path is the path to the executable
+	Path string
+}
+
+// FindInstalledProviders walks a plugin cache directory (or a .terraform/providers
+// directory), which uses the layout of HOSTNAME/NAMESPACE/TYPE/VERSION/OS_ARCH
+// and returns providers built for the current platform.
+//
+// All installed versions of each provider are returned, sorted by address
+// and then by version, such that the newest version of each provider
+// comes last.
+func FindInstalledProviders(dir string) ([]*InstalledProvider, error) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "*", "*", "*", platform))
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]*InstalledProvider, 0)
+
+	for _, platformDir := range matches {
+		rel, err := filepath.Rel(dir, platformDir)
+		if err != nil {
+			return nil, err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		hostname, namespace, typeName, rawVersion := parts[0], parts[1], parts[2], parts[3]
+
+		addr, err := addrs.ParseProviderSourceString(
+			fmt.Sprintf("%s/%s/%s", hostname, namespace, typeName))
+		if err != nil {
+			// not a provider directory
+			continue
+		}
+
+		v, err := version.NewVersion(rawVersion)
+		if err != nil {
+			continue
+		}
+
+		path, err := findExecutable(platformDir, addr.Type)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			continue
+		}
+
+		providers = append(providers, &InstalledProvider{
+			Address: addr.String(),
+			Version: v,
+			Path:    path,
+		})
+	}
+
+	sort.SliceStable(providers, func(i, j int) bool {
+		if providers[i].Address != providers[j].Address {
+			return providers[i].Address < providers[j].Address
+		}
+		return providers[i].Version.LessThan(providers[j].Version)
+	})
+
+	return providers, nil
+}
+
+func findExecutable(dir, typeName string) (string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := "terraform-provider-" + typeName
+	for _, info := range infos {
+		if !strings.HasPrefix(info.Name(), prefix) {
+			continue
+		}
+		if !info.Mode().IsRegular() && info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+		return filepath.Join(dir, info.Name()), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,88 @@
+package providerplugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindInstalledProviders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bit is not available on Windows")
+	}
+
+	dir, err := ioutil.TempDir("", "plugin-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	files := map[string]os.FileMode{
+		"registry.terraform.io/hashicorp/aws/3.10.0/" + platform + "/terraform-provider-aws_v3.10.0_x5": 0755,
+		"registry.terraform.io/hashicorp/aws/3.12.0/" + platform + "/terraform-provider-aws_v3.12.0_x5": 0755,
+		"example.com/acme/foo/0.1.0/" + platform + "/terraform-provider-foo":                            0755,
+		"example.com/acme/foo/0.1.0/" + platform + "/README.md":                                         0644,
+		// not executable
+		"example.com/acme/bar/0.1.0/" + platform + "/terraform-provider-bar": 0644,
+		// different platform
+		"example.com/acme/baz/0.1.0/plan9_mips/terraform-provider-baz": 0755,
+		// invalid version
+		"example.com/acme/qux/latest/" + platform + "/terraform-provider-qux": 0755,
+	}
+	for path, mode := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		err := os.MkdirAll(filepath.Dir(fullPath), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(fullPath, []byte{}, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	providers, err := FindInstalledProviders(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type provider struct {
+		Address string
+		Version string
+		Path    string
+	}
+	expectedProviders := []provider{
+		{
+			Address: "example.com/acme/foo",
+			Version: "0.1.0",
+			Path:    filepath.Join(dir, "example.com", "acme", "foo", "0.1.0", platform, "terraform-provider-foo"),
+		},
+		{
+			Address: "registry.terraform.io/hashicorp/aws",
+			Version: "3.10.0",
+			Path:    filepath.Join(dir, "registry.terraform.io", "hashicorp", "aws", "3.10.0", platform, "terraform-provider-aws_v3.10.0_x5"),
+		},
+		{
+			Address: "registry.terraform.io/hashicorp/aws",
+			Version: "3.12.0",
+			Path:    filepath.Join(dir, "registry.terraform.io", "hashicorp", "aws", "3.12.0", platform, "terraform-provider-aws_v3.12.0_x5"),
+		},
+	}
+	givenProviders := make([]provider, len(providers))
+	for i, p := range providers {
+		givenProviders[i] = provider{
+			Address: p.Address,
+			Version: p.Version.String(),
+			Path:    p.Path,
+		}
+	}
+
+	if diff := cmp.Diff(expectedProviders, givenProviders); diff != "" {
+		t.Fatalf("providers mismatch: %s", diff)
+	}
+}
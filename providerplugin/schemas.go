@@ -0,0 +1,63 @@
+// Package providerplugin obtains provider schemas directly from
+// installed provider binaries via the plugin protocol (tfplugin5 or tfplugin6),
+// as an alternative to running `terraform providers schema -json`,
+// which requires valid configuration and initialized backend.
+package providerplugin
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-multierror"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// LoadProviderSchemas finds all providers installed in the given
+// plugin cache directory and obtains schemas from them.
+//
+// As terraform-json formatted schemas are keyed by provider address,
+// only the newest installed version of each provider is loaded.
+// Schemas of other versions can be obtained via FindInstalledProviders
+// and InstalledProvider.ProviderSchema.
+//
+// If schemas of some providers cannot be obtained, schemas of the remaining
+// providers are still returned, along with a *multierror.Error
+// containing an error for each failed provider.
+//
+// The result can be passed to schema.SchemaMerger.MergeWithJsonProviderSchemas.
+func LoadProviderSchemas(ctx context.Context, dir string, opts ...ClientOption) (*tfjson.ProviderSchemas, error) {
+	providers, err := FindInstalledProviders(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &tfjson.ProviderSchemas{
+		FormatVersion: tfjson.ProviderSchemasFormatVersion,
+		Schemas:       make(map[string]*tfjson.ProviderSchema, 0),
+	}
+
+	var errs *multierror.Error
+	for _, p := range newestProviders(providers) {
+		schema, err := p.ProviderSchema(ctx, opts...)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		ps.Schemas[p.Address] = schema
+	}
+
+	return ps, errs.ErrorOrNil()
+}
+
+// newestProviders returns the newest version of each provider
+// from providers sorted as returned by FindInstalledProviders
+func newestProviders(providers []*InstalledProvider) []*InstalledProvider {
+	newest := make([]*InstalledProvider, 0)
+	for _, p := range providers {
+		if len(newest) > 0 && newest[len(newest)-1].Address == p.Address {
+			newest[len(newest)-1] = p
+			continue
+		}
+		newest = append(newest, p)
+	}
+	return newest
+}
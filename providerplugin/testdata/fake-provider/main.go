@@ -0,0 +1,277 @@
+// fake-provider is a minimal provider which implements
+// only the handshake and GetProviderSchema RPC for testing purposes.
+//
+// Responses are built from types generated from tfplugin5.proto
+// and tfplugin6.proto.
+//
+// The protocol version is set at build time via
+// -ldflags "-X main.protocolVersion=6"
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin5"
+	"github.com/hashicorp/terraform-schema/providerplugin/internal/tfplugin6"
+	"google.golang.org/grpc"
+)
+
+var protocolVersion = "5"
+
+func main() {
+	if os.Getenv("TF_PLUGIN_MAGIC_COOKIE") != "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2" {
+		fmt.Fprintln(os.Stderr, "This binary is a plugin and is not meant to be executed directly.")
+		os.Exit(1)
+	}
+
+	supported := strings.Split(os.Getenv("PLUGIN_PROTOCOL_VERSIONS"), ",")
+	if !contains(supported, protocolVersion) {
+		fmt.Fprintf(os.Stderr, "unsupported protocol versions: %q\n", supported)
+		os.Exit(1)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	srv := grpc.NewServer()
+	srv.RegisterService(serviceDesc(), struct{}{})
+
+	fmt.Printf("1|%s|tcp|%s|grpc\n", protocolVersion, l.Addr().String())
+
+	srv.Serve(l)
+}
+
+func serviceDesc() *grpc.ServiceDesc {
+	serviceName, methodName := "tfplugin5.Provider", "GetSchema"
+	if protocolVersion == "6" {
+		serviceName, methodName = "tfplugin6.Provider", "GetProviderSchema"
+	}
+
+	return &grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: methodName,
+				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					if protocolVersion == "6" {
+						if err := dec(&tfplugin6.GetProviderSchema_Request{}); err != nil {
+							return nil, err
+						}
+						return getProviderSchemaResponse6(), nil
+					}
+
+					if err := dec(&tfplugin5.GetProviderSchema_Request{}); err != nil {
+						return nil, err
+					}
+					return getProviderSchemaResponse5(), nil
+				},
+			},
+		},
+	}
+}
+
+func getProviderSchemaResponse5() *tfplugin5.GetProviderSchema_Response {
+	if summary := os.Getenv("FAKE_PROVIDER_ERROR"); summary != "" {
+		return &tfplugin5.GetProviderSchema_Response{
+			Diagnostics: []*tfplugin5.Diagnostic{
+				{
+					Severity: tfplugin5.Diagnostic_ERROR,
+					Summary:  summary,
+					Detail:   "something went wrong",
+				},
+			},
+		}
+	}
+
+	return &tfplugin5.GetProviderSchema_Response{
+		Provider: &tfplugin5.Schema{
+			Block: &tfplugin5.Schema_Block{
+				Attributes: []*tfplugin5.Schema_Attribute{
+					{
+						Name:            "region",
+						Type:            []byte(`"string"`),
+						Description:     "Region to use",
+						Optional:        true,
+						DescriptionKind: tfplugin5.StringKind_MARKDOWN,
+					},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*tfplugin5.Schema{
+			"fake_instance": {
+				Version: 2,
+				Block: &tfplugin5.Schema_Block{
+					Attributes: []*tfplugin5.Schema_Attribute{
+						{
+							Name:     "name",
+							Type:     []byte(`"string"`),
+							Required: true,
+						},
+						{
+							Name:      "password",
+							Type:      []byte(`"string"`),
+							Optional:  true,
+							Sensitive: true,
+						},
+					},
+					BlockTypes: []*tfplugin5.Schema_NestedBlock{
+						{
+							TypeName: "timeouts",
+							Block: &tfplugin5.Schema_Block{
+								Attributes: []*tfplugin5.Schema_Attribute{
+									{
+										Name:     "create",
+										Type:     []byte(`"string"`),
+										Optional: true,
+									},
+								},
+							},
+							Nesting: tfplugin5.Schema_NestedBlock_SINGLE,
+						},
+					},
+					Description: "Fake instance",
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*tfplugin5.Schema{
+			"fake_image": {
+				Block: &tfplugin5.Schema_Block{
+					Attributes: []*tfplugin5.Schema_Attribute{
+						{
+							Name:     "id",
+							Type:     []byte(`"string"`),
+							Computed: true,
+						},
+					},
+					Deprecated: true,
+				},
+			},
+		},
+		Diagnostics: []*tfplugin5.Diagnostic{
+			// warnings are not expected to fail the request
+			{
+				Severity: tfplugin5.Diagnostic_WARNING,
+				Summary:  "fake warning",
+			},
+		},
+	}
+}
+
+func getProviderSchemaResponse6() *tfplugin6.GetProviderSchema_Response {
+	if summary := os.Getenv("FAKE_PROVIDER_ERROR"); summary != "" {
+		return &tfplugin6.GetProviderSchema_Response{
+			Diagnostics: []*tfplugin6.Diagnostic{
+				{
+					Severity: tfplugin6.Diagnostic_ERROR,
+					Summary:  summary,
+					Detail:   "something went wrong",
+				},
+			},
+		}
+	}
+
+	return &tfplugin6.GetProviderSchema_Response{
+		Provider: &tfplugin6.Schema{
+			Block: &tfplugin6.Schema_Block{
+				Attributes: []*tfplugin6.Schema_Attribute{
+					{
+						Name:            "region",
+						Type:            []byte(`"string"`),
+						Description:     "Region to use",
+						Optional:        true,
+						DescriptionKind: tfplugin6.StringKind_MARKDOWN,
+					},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*tfplugin6.Schema{
+			"fake_instance": {
+				Version: 2,
+				Block: &tfplugin6.Schema_Block{
+					Attributes: []*tfplugin6.Schema_Attribute{
+						{
+							Name:     "name",
+							Type:     []byte(`"string"`),
+							Required: true,
+						},
+						{
+							Name:      "password",
+							Type:      []byte(`"string"`),
+							Optional:  true,
+							Sensitive: true,
+						},
+						{
+							Name: "settings",
+							NestedType: &tfplugin6.Schema_Object{
+								Attributes: []*tfplugin6.Schema_Attribute{
+									{
+										Name:     "key",
+										Type:     []byte(`"string"`),
+										Required: true,
+									},
+								},
+								Nesting:  tfplugin6.Schema_Object_LIST,
+								MinItems: 1,
+							},
+							Optional: true,
+						},
+					},
+					BlockTypes: []*tfplugin6.Schema_NestedBlock{
+						{
+							TypeName: "timeouts",
+							Block: &tfplugin6.Schema_Block{
+								Attributes: []*tfplugin6.Schema_Attribute{
+									{
+										Name:     "create",
+										Type:     []byte(`"string"`),
+										Optional: true,
+									},
+								},
+							},
+							Nesting: tfplugin6.Schema_NestedBlock_SINGLE,
+						},
+					},
+					Description: "Fake instance",
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*tfplugin6.Schema{
+			"fake_image": {
+				Block: &tfplugin6.Schema_Block{
+					Attributes: []*tfplugin6.Schema_Attribute{
+						{
+							Name:     "id",
+							Type:     []byte(`"string"`),
+							Computed: true,
+						},
+					},
+					Deprecated: true,
+				},
+			},
+		},
+		Diagnostics: []*tfplugin6.Diagnostic{
+			// warnings are not expected to fail the request
+			{
+				Severity: tfplugin6.Diagnostic_WARNING,
+				Summary:  "fake warning",
+			},
+		},
+	}
+}
+
+func contains(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
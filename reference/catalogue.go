@@ -0,0 +1,396 @@
+package reference
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/hashicorp/terraform-schema/internal/module"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var rootSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "locals",
+		},
+		{
+			Type:       "resource",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var resourceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+		{
+			Name: "provider",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "provisioner",
+			LabelNames: []string{"type"},
+		},
+		{
+			Type: "connection",
+		},
+	},
+}
+
+var moduleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+	},
+}
+
+// BuildCatalogue builds a catalogue of reference targets available
+// in the module represented by the given files.
+//
+// Types of resources and data sources are derived from the given
+// (merged) body schema. Outputs of called modules can be provided via
+// modules, as obtained from SchemaMerger.ModuleCallOutputs.
+// Both body schema and modules are optional.
+func BuildCatalogue(files map[string]*hcl.File, bodySchema *schema.BodySchema,
+	modules map[string]*tfschema.ModuleCallOutputs) (*Catalogue, hcl.Diagnostics) {
+	c := &Catalogue{
+		Targets: builtinTargets(),
+	}
+
+	variables, diags := module.DecodeVariables(files)
+	for name, v := range variables {
+		defRange := v.DefRange
+		t := &Target{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "var"},
+				lang.AttrStep{Name: name},
+			},
+			Type:        withoutOptionalAttrs(v.Type),
+			DefRange:    &defRange,
+			IsSensitive: v.IsSensitive,
+		}
+		if v.Description != "" {
+			t.Description = lang.PlainText(v.Description)
+		}
+		c.Targets = append(c.Targets, t)
+	}
+
+	var countScopes, forEachScopes []hcl.Range
+
+	for _, f := range files {
+		if f == nil || f.Body == nil {
+			continue
+		}
+		content, _, contentDiags := f.Body.PartialContent(rootSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "locals":
+				attrs, attrDiags := block.Body.JustAttributes()
+				diags = append(diags, attrDiags...)
+				for name, attr := range attrs {
+					c.Targets = append(c.Targets, localTarget(name, attr))
+				}
+			case "resource", "data":
+				targets, hasCount, hasForEach, rDiags := resourceTargets(block, bodySchema)
+				diags = append(diags, rDiags...)
+				c.Targets = append(c.Targets, targets...)
+				if hasCount {
+					countScopes = append(countScopes, bodyRange(block))
+				}
+				if hasForEach {
+					forEachScopes = append(forEachScopes, bodyRange(block))
+				}
+			case "module":
+				mContent, _, mDiags := block.Body.PartialContent(moduleBlockSchema)
+				diags = append(diags, mDiags...)
+				_, hasCount := mContent.Attributes["count"]
+				_, hasForEach := mContent.Attributes["for_each"]
+
+				c.Targets = append(c.Targets, moduleTargets(block, modules[block.Labels[0]],
+					hasCount, hasForEach)...)
+				if hasCount {
+					countScopes = append(countScopes, bodyRange(block))
+				}
+				if hasForEach {
+					forEachScopes = append(forEachScopes, bodyRange(block))
+				}
+			}
+		}
+	}
+
+	if len(countScopes) > 0 {
+		c.Targets = append(c.Targets, &Target{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "count"},
+				lang.AttrStep{Name: "index"},
+			},
+			Type:        cty.Number,
+			Description: lang.PlainText("Index of the current instance (starting with 0)"),
+			ScopeRanges: countScopes,
+		})
+	}
+	if len(forEachScopes) > 0 {
+		c.Targets = append(c.Targets, &Target{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "each"},
+				lang.AttrStep{Name: "key"},
+			},
+			Type:        cty.String,
+			Description: lang.PlainText("Map key (or set member) of the current instance"),
+			ScopeRanges: forEachScopes,
+		}, &Target{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "each"},
+				lang.AttrStep{Name: "value"},
+			},
+			Type:        cty.DynamicPseudoType,
+			Description: lang.PlainText("Map value (or set member) of the current instance"),
+			ScopeRanges: forEachScopes,
+		})
+	}
+
+	c.sort()
+
+	return c, diags
+}
+
+func builtinTargets() []*Target {
+	return []*Target{
+		{
+			Addr:        lang.Reference{lang.RootStep{Name: "path"}, lang.AttrStep{Name: "module"}},
+			Type:        cty.String,
+			Description: lang.PlainText("Filesystem path of the module where the expression is placed"),
+		},
+		{
+			Addr:        lang.Reference{lang.RootStep{Name: "path"}, lang.AttrStep{Name: "root"}},
+			Type:        cty.String,
+			Description: lang.PlainText("Filesystem path of the root module of the configuration"),
+		},
+		{
+			Addr:        lang.Reference{lang.RootStep{Name: "path"}, lang.AttrStep{Name: "cwd"}},
+			Type:        cty.String,
+			Description: lang.PlainText("Filesystem path of the current working directory"),
+		},
+		{
+			Addr:        lang.Reference{lang.RootStep{Name: "terraform"}, lang.AttrStep{Name: "workspace"}},
+			Type:        cty.String,
+			Description: lang.PlainText("Name of the currently selected workspace"),
+		},
+	}
+}
+
+func localTarget(name string, attr *hcl.Attribute) *Target {
+	ty := cty.DynamicPseudoType
+	val, diags := attr.Expr.Value(nil)
+	if !diags.HasErrors() {
+		ty = val.Type()
+	}
+
+	defRange := attr.Range
+	return &Target{
+		Addr: lang.Reference{
+			lang.RootStep{Name: "local"},
+			lang.AttrStep{Name: name},
+		},
+		Type:     ty,
+		DefRange: &defRange,
+	}
+}
+
+func resourceTargets(block *hcl.Block, bodySchema *schema.BodySchema) ([]*Target, bool, bool, hcl.Diagnostics) {
+	content, _, diags := block.Body.PartialContent(resourceBlockSchema)
+	_, hasCount := content.Attributes["count"]
+	_, hasForEach := content.Attributes["for_each"]
+
+	resourceType, name := block.Labels[0], block.Labels[1]
+
+	var providerAttr hcl.Traversal
+	if attr, ok := content.Attributes["provider"]; ok {
+		traversal, travDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		if !travDiags.HasErrors() {
+			providerAttr = traversal
+		}
+	}
+
+	objType := objectTypeForBody(resourceBodySchema(bodySchema, block.Type, resourceType, providerAttr))
+
+	addr := lang.Reference{
+		lang.RootStep{Name: resourceType},
+		lang.AttrStep{Name: name},
+	}
+	if block.Type == "data" {
+		addr = lang.Reference{
+			lang.RootStep{Name: "data"},
+			lang.AttrStep{Name: resourceType},
+			lang.AttrStep{Name: name},
+		}
+	}
+
+	defRange := block.DefRange
+	targets := []*Target{
+		{
+			Addr:     addr,
+			Type:     typeForInstances(objType, hasCount, hasForEach),
+			DefRange: &defRange,
+		},
+	}
+
+	if block.Type == "resource" {
+		// self refers to the current instance
+		// within provisioner and connection blocks
+		selfScopes := make([]hcl.Range, 0)
+		for _, b := range content.Blocks {
+			selfScopes = append(selfScopes, bodyRange(b))
+		}
+		if len(selfScopes) > 0 {
+			targets = append(targets, &Target{
+				Addr: lang.Reference{
+					lang.RootStep{Name: "self"},
+				},
+				Type:        objType,
+				ScopeRanges: selfScopes,
+			})
+		}
+	}
+
+	return targets, hasCount, hasForEach, diags
+}
+
+// resourceBodySchema looks up the schema of a resource (or data source)
+// using the same dependency keys which the schema merger uses
+func resourceBodySchema(bodySchema *schema.BodySchema, blockType, resourceType string,
+	providerAttr hcl.Traversal) *schema.BodySchema {
+	if bodySchema == nil {
+		return nil
+	}
+	blockSchema, ok := bodySchema.Blocks[blockType]
+	if !ok {
+		return nil
+	}
+
+	labelOnlyKeys := schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: resourceType},
+		},
+	}
+
+	cfg, err := addrs.ResourceProviderConfig(resourceType, providerAttr)
+	if err == nil && (cfg.Alias != "" || cfg.LocalName != addrs.ImpliedProviderLocalName(resourceType)) {
+		ref := lang.Reference{
+			lang.RootStep{Name: cfg.LocalName},
+		}
+		if cfg.Alias != "" {
+			ref = append(ref, lang.AttrStep{Name: cfg.Alias})
+		}
+		keys := schema.DependencyKeys{
+			Labels: labelOnlyKeys.Labels,
+			Attributes: []schema.AttributeDependent{
+				{
+					Name: "provider",
+					Expr: schema.ExpressionValue{
+						Reference: ref,
+					},
+				},
+			},
+		}
+		if bs, ok := blockSchema.DependentBodySchema(keys); ok {
+			return bs
+		}
+	}
+
+	bs, _ := blockSchema.DependentBodySchema(labelOnlyKeys)
+	return bs
+}
+
+func moduleTargets(block *hcl.Block, mco *tfschema.ModuleCallOutputs, hasCount, hasForEach bool) []*Target {
+	name := block.Labels[0]
+	defRange := block.DefRange
+
+	if mco == nil {
+		// outputs are not known, e.g. for remote modules
+		return []*Target{
+			{
+				Addr: lang.Reference{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: name},
+				},
+				Type:     cty.DynamicPseudoType,
+				DefRange: &defRange,
+			},
+		}
+	}
+
+	targets := []*Target{
+		{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: name},
+			},
+			Type:     mco.Type(),
+			DefRange: &defRange,
+		},
+	}
+
+	if hasCount || hasForEach {
+		// individual outputs are only addressable
+		// via particular instances
+		return targets
+	}
+
+	for outputName, output := range mco.Outputs {
+		targets = append(targets, &Target{
+			Addr: lang.Reference{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: name},
+				lang.AttrStep{Name: outputName},
+			},
+			Type:        cty.DynamicPseudoType,
+			Description: output.Description,
+			DefRange:    &defRange,
+			IsSensitive: output.IsSensitive,
+		})
+	}
+
+	return targets
+}
+
+// bodyRange returns range of the whole block, or range
+// of its header if the range of the body is not available
+func bodyRange(block *hcl.Block) hcl.Range {
+	if body, ok := block.Body.(interface{ Range() hcl.Range }); ok {
+		return hcl.RangeBetween(block.DefRange, body.Range())
+	}
+
+	// JSON bodies do not expose their range, but the definition
+	// range of JSON blocks is the opening brace of the body
+	// and the missing item range is the closing brace
+	missingRange := block.Body.MissingItemRange()
+	if missingRange.Filename == block.DefRange.Filename &&
+		missingRange.End.Byte > block.DefRange.End.Byte {
+		return hcl.RangeBetween(block.DefRange, missingRange)
+	}
+
+	return block.DefRange
+}
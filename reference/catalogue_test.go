@@ -0,0 +1,403 @@
+package reference
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	tfjson "github.com/hashicorp/terraform-json"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testConfig = `variable "name" {
+  type        = string
+  description = "Name of the instance"
+}
+
+variable "password" {
+  sensitive = true
+}
+
+locals {
+  count = 2
+  tags  = { env = "dev" }
+}
+
+resource "aws_instance" "web" {
+  count = local.count
+
+  provisioner "local-exec" {
+    command = "echo ${self.id}"
+  }
+}
+
+data "aws_ami" "ubuntu" {
+  for_each = toset(["a", "b"])
+}
+
+module "network" {
+  source = "./network"
+}
+
+variable "settings" {
+  type = object({ port = optional(number) })
+}
+`
+
+func TestBuildCatalogue(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(testConfig), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	files := map[string]*hcl.File{
+		"main.tf": f,
+	}
+
+	bodySchema := testMergedSchema(t, files)
+	modules := map[string]*tfschema.ModuleCallOutputs{
+		"network": {
+			Name:   "network",
+			Source: "./network",
+			Outputs: map[string]*tfschema.ModuleOutput{
+				"vpc_id": {
+					Name:        "vpc_id",
+					Description: lang.PlainText("ID of the VPC"),
+				},
+			},
+		},
+	}
+
+	c, diags := BuildCatalogue(files, bodySchema, modules)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	instanceType := cty.Object(map[string]cty.Type{
+		"id":            cty.String,
+		"instance_type": cty.String,
+		"ebs_block_device": cty.List(cty.Object(map[string]cty.Type{
+			"device_name": cty.String,
+		})),
+	})
+	amiType := cty.Object(map[string]cty.Type{
+		"id": cty.String,
+	})
+
+	expectedTypes := map[string]cty.Type{
+		"count.index":           cty.Number,
+		"data.aws_ami.ubuntu":   cty.Map(amiType),
+		"each.key":              cty.String,
+		"each.value":            cty.DynamicPseudoType,
+		"local.count":           cty.Number,
+		"local.tags":            cty.Object(map[string]cty.Type{"env": cty.String}),
+		"module.network":        cty.Object(map[string]cty.Type{"vpc_id": cty.DynamicPseudoType}),
+		"module.network.vpc_id": cty.DynamicPseudoType,
+		"path.cwd":              cty.String,
+		"path.module":           cty.String,
+		"path.root":             cty.String,
+		"self":                  instanceType,
+		"terraform.workspace":   cty.String,
+		"var.name":              cty.String,
+		"var.password":          cty.DynamicPseudoType,
+		"var.settings":          cty.Object(map[string]cty.Type{"port": cty.Number}),
+		"aws_instance.web":      cty.List(instanceType),
+	}
+
+	givenTypes := make(map[string]cty.Type, 0)
+	for _, target := range c.Targets {
+		givenTypes[addrString(target.Addr)] = target.Type
+	}
+	if diff := cmp.Diff(expectedTypes, givenTypes, cmp.Comparer(cty.Type.Equals)); diff != "" {
+		t.Fatalf("target types mismatch: %s", diff)
+	}
+
+	target, ok := c.Find(lang.Reference{lang.RootStep{Name: "var"}, lang.AttrStep{Name: "password"}},
+		"main.tf", hcl.InitialPos)
+	if !ok {
+		t.Fatal("expected var.password to be found")
+	}
+	if !target.IsSensitive {
+		t.Fatal("expected var.password to be sensitive")
+	}
+}
+
+func TestCatalogue_TargetsInScope(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(testConfig), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	c, diags := BuildCatalogue(map[string]*hcl.File{
+		"main.tf": f,
+	}, nil, nil)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	scopedTargets := []string{"count.index", "each.key", "each.value", "self"}
+
+	testCases := []struct {
+		pos             hcl.Pos
+		expectedInScope []string
+	}{
+		{
+			// variable block
+			hcl.Pos{Line: 2, Column: 3, Byte: 20},
+			[]string{},
+		},
+		{
+			// resource block (count)
+			hcl.Pos{Line: 16, Column: 18, Byte: 226},
+			[]string{"count.index"},
+		},
+		{
+			// provisioner block within counted resource
+			hcl.Pos{Line: 19, Column: 21, Byte: 281},
+			[]string{"count.index", "self"},
+		},
+		{
+			// data block (for_each)
+			hcl.Pos{Line: 24, Column: 16, Byte: 341},
+			[]string{"each.key", "each.value"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%d:%d", i, tc.pos.Line, tc.pos.Column), func(t *testing.T) {
+			inScope := make([]string, 0)
+			for _, target := range c.TargetsInScope("main.tf", tc.pos) {
+				addr := addrString(target.Addr)
+				for _, scoped := range scopedTargets {
+					if addr == scoped {
+						inScope = append(inScope, addr)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedInScope, inScope); diff != "" {
+				t.Fatalf("targets in scope mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCatalogue_Find(t *testing.T) {
+	cfg := `resource "aws_instance" "web" {
+  provisioner "local-exec" {
+    command = "echo ${self.id}"
+  }
+}
+
+resource "aws_eip" "web" {
+  connection {
+    host = self.public_ip
+  }
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	c, diags := BuildCatalogue(map[string]*hcl.File{
+		"main.tf": f,
+	}, nil, nil)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	self := lang.Reference{lang.RootStep{Name: "self"}}
+
+	testCases := []struct {
+		pos           hcl.Pos
+		expectedFound bool
+		expectedScope hcl.Pos
+	}{
+		{
+			// outside of any provisioner or connection block
+			hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			false,
+			hcl.Pos{},
+		},
+		{
+			// provisioner block of aws_instance.web
+			hcl.Pos{Line: 3, Column: 20, Byte: 80},
+			true,
+			hcl.Pos{Line: 2, Column: 3, Byte: 34},
+		},
+		{
+			// connection block of aws_eip.web
+			hcl.Pos{Line: 9, Column: 12, Byte: 153},
+			true,
+			hcl.Pos{Line: 8, Column: 3, Byte: 129},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%d:%d", i, tc.pos.Line, tc.pos.Column), func(t *testing.T) {
+			target, ok := c.Find(self, "main.tf", tc.pos)
+			if ok != tc.expectedFound {
+				t.Fatalf("expected found: %t, given: %t", tc.expectedFound, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tc.expectedScope, target.ScopeRanges[0].Start); diff != "" {
+				t.Fatalf("scope mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+const testJsonConfig = `{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "count": 2,
+        "ami": "ami-${count.index}",
+        "provisioner": {
+          "local-exec": {
+            "command": "echo ${self.id}"
+          }
+        }
+      }
+    }
+  },
+  "data": {
+    "aws_ami": {
+      "ubuntu": {
+        "for_each": ["a", "b"],
+        "name": "${each.key}"
+      }
+    }
+  },
+  "variable": {
+    "name": {
+      "type": "string"
+    }
+  }
+}
+`
+
+func TestCatalogue_TargetsInScope_json(t *testing.T) {
+	f, diags := json.Parse([]byte(testJsonConfig), "main.tf.json")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	c, diags := BuildCatalogue(map[string]*hcl.File{
+		"main.tf.json": f,
+	}, nil, nil)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	scopedTargets := []string{"count.index", "each.key", "each.value", "self"}
+
+	testCases := []struct {
+		substr          string
+		expectedInScope []string
+	}{
+		{
+			`"type"`,
+			[]string{},
+		},
+		{
+			`"ami"`,
+			[]string{"count.index"},
+		},
+		{
+			`"command"`,
+			[]string{"count.index", "self"},
+		},
+		{
+			`"name": "${each.key}"`,
+			[]string{"each.key", "each.value"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.substr), func(t *testing.T) {
+			pos := posOf(t, testJsonConfig, tc.substr)
+			inScope := make([]string, 0)
+			for _, target := range c.TargetsInScope("main.tf.json", pos) {
+				addr := addrString(target.Addr)
+				for _, scoped := range scopedTargets {
+					if addr == scoped {
+						inScope = append(inScope, addr)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedInScope, inScope); diff != "" {
+				t.Fatalf("targets in scope mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+// posOf returns position of the first occurrence of substr in src
+func posOf(t *testing.T, src, substr string) hcl.Pos {
+	idx := strings.Index(src, substr)
+	if idx < 0 {
+		t.Fatalf("%q not found", substr)
+	}
+	line := strings.Count(src[:idx], "\n") + 1
+	column := idx - strings.LastIndex(src[:idx], "\n")
+	return hcl.Pos{Line: line, Column: column, Byte: idx}
+}
+
+func testMergedSchema(t *testing.T, files map[string]*hcl.File) *schema.BodySchema {
+	coreSchema, err := tfschema.CoreModuleSchemaForVersion(version.Must(version.NewVersion("0.15.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm := tfschema.NewSchemaMerger(coreSchema)
+	sm.SetParsedFiles(files)
+	bs, _, err := sm.MergeWithJsonProviderSchemas(&tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_instance": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"id":            {AttributeType: cty.String, Computed: true},
+								"instance_type": {AttributeType: cty.String, Required: true},
+							},
+							NestedBlocks: map[string]*tfjson.SchemaBlockType{
+								"ebs_block_device": {
+									NestingMode: tfjson.SchemaNestingModeList,
+									Block: &tfjson.SchemaBlock{
+										Attributes: map[string]*tfjson.SchemaAttribute{
+											"device_name": {AttributeType: cty.String, Required: true},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				DataSourceSchemas: map[string]*tfjson.Schema{
+					"aws_ami": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"id": {AttributeType: cty.String, Computed: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
@@ -0,0 +1,94 @@
+// Package reference builds a catalogue of reference targets
+// (such as var.foo, aws_instance.web or count.index)
+// available within a module, along with their types
+package reference
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Target represents a single addressable object
+// which can be referenced from within the module
+type Target struct {
+	Addr        lang.Reference
+	Type        cty.Type
+	Description lang.MarkupContent
+
+	// DefRange is the range of the declaration,
+	// nil for targets which are not declared in configuration
+	// (e.g. path.module)
+	DefRange *hcl.Range
+
+	// ScopeRanges restricts where the target can be referenced.
+	// Targets with no scope ranges can be referenced anywhere
+	// within the module.
+	ScopeRanges []hcl.Range
+
+	IsSensitive bool
+}
+
+// IsInScope returns true if the target can be referenced
+// from the given position in the given file
+func (t *Target) IsInScope(filename string, pos hcl.Pos) bool {
+	if len(t.ScopeRanges) == 0 {
+		return true
+	}
+	for _, rng := range t.ScopeRanges {
+		if rng.Filename == filename && rng.ContainsOffset(pos.Byte) {
+			return true
+		}
+	}
+	return false
+}
+
+// Catalogue is a collection of reference targets of a single module
+type Catalogue struct {
+	Targets []*Target
+}
+
+// Find returns the target with the given address which can be
+// referenced from the given position in the given file, if any.
+//
+// Scope matters for targets such as self or count.index,
+// which share the same address across all blocks declaring them.
+func (c *Catalogue) Find(addr lang.Reference, filename string, pos hcl.Pos) (*Target, bool) {
+	key := addrString(addr)
+	for _, t := range c.Targets {
+		if addrString(t.Addr) == key && t.IsInScope(filename, pos) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TargetsInScope returns all targets which can be referenced
+// from the given position in the given file
+func (c *Catalogue) TargetsInScope(filename string, pos hcl.Pos) []*Target {
+	targets := make([]*Target, 0)
+	for _, t := range c.Targets {
+		if t.IsInScope(filename, pos) {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+func (c *Catalogue) sort() {
+	sort.SliceStable(c.Targets, func(i, j int) bool {
+		return bytes.Compare(addrBytes(c.Targets[i].Addr), addrBytes(c.Targets[j].Addr)) < 0
+	})
+}
+
+func addrString(addr lang.Reference) string {
+	return string(addrBytes(addr))
+}
+
+func addrBytes(addr lang.Reference) []byte {
+	b, _ := addr.Marshal()
+	return b
+}
@@ -0,0 +1,52 @@
+package reference
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/terraform-schema/internal/bodytype"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// objectTypeForBody returns type of a value (e.g. a resource)
+// described by the given body schema
+func objectTypeForBody(bs *schema.BodySchema) cty.Type {
+	return withoutOptionalAttrs(bodytype.ObjectType(bs))
+}
+
+// typeForInstances returns type of a reference to a resource
+// or module with count or for_each
+func typeForInstances(ty cty.Type, hasCount, hasForEach bool) cty.Type {
+	switch {
+	case hasCount:
+		return cty.List(ty)
+	case hasForEach:
+		return cty.Map(ty)
+	}
+	return ty
+}
+
+// withoutOptionalAttrs returns the given type with any optional
+// object attributes converted to regular ones, since values
+// (unlike type constraints) always have all attributes
+func withoutOptionalAttrs(ty cty.Type) cty.Type {
+	switch {
+	case ty.IsObjectType():
+		attrTypes := make(map[string]cty.Type, len(ty.AttributeTypes()))
+		for name, aty := range ty.AttributeTypes() {
+			attrTypes[name] = withoutOptionalAttrs(aty)
+		}
+		return cty.Object(attrTypes)
+	case ty.IsListType():
+		return cty.List(withoutOptionalAttrs(ty.ElementType()))
+	case ty.IsSetType():
+		return cty.Set(withoutOptionalAttrs(ty.ElementType()))
+	case ty.IsMapType():
+		return cty.Map(withoutOptionalAttrs(ty.ElementType()))
+	case ty.IsTupleType():
+		elemTypes := make([]cty.Type, len(ty.TupleElementTypes()))
+		for i, ety := range ty.TupleElementTypes() {
+			elemTypes[i] = withoutOptionalAttrs(ety)
+		}
+		return cty.Tuple(elemTypes)
+	}
+	return ty
+}
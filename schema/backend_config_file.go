@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+// BackendConfigFileSchema returns the body schema of partial backend
+// configuration files (e.g. *.tfbackend files passed via -backend-config)
+// for the given backend type and version of Terraform.
+//
+// The schema is a copy of the one of the backend block of the given type,
+// in which nothing is required, as the remaining configuration
+// may be provided via the backend block or other files.
+func BackendConfigFileSchema(backendType string, v *version.Version) (*schema.BodySchema, error) {
+	coreSchema, err := CoreModuleSchemaForVersion(v)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, ok := backendBodySchema(coreSchema, backendType)
+	if !ok {
+		return nil, unknownBackendErr{
+			backendType: backendType,
+			version:     v.String(),
+		}
+	}
+
+	return copyBodySchema(bs), nil
+}
+
+// backendBodySchema looks up the body of the backend of the given type
+// among dependent bodies of the backend block within the terraform block
+func backendBodySchema(coreSchema *schema.BodySchema, backendType string) (*schema.BodySchema, bool) {
+	tfBlock, ok := coreSchema.Blocks["terraform"]
+	if !ok || tfBlock.Body == nil {
+		return nil, false
+	}
+	backendBlock, ok := tfBlock.Body.Blocks["backend"]
+	if !ok {
+		return nil, false
+	}
+
+	return backendBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: backendType},
+		},
+	})
+}
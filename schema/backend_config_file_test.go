@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestBackendConfigFileSchema(t *testing.T) {
+	v := version.Must(version.NewVersion("1.2.0"))
+
+	bs, err := BackendConfigFileSchema("s3", v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket, ok := bs.Attributes["bucket"]
+	if !ok {
+		t.Fatal("expected bucket attribute")
+	}
+	if bucket.IsRequired || !bucket.IsOptional {
+		t.Fatalf("expected bucket to be optional in partial configuration: %#v", bucket)
+	}
+
+	// the core schema is left intact
+	bucket.Description.Value = "changed"
+	coreSchema, err := CoreModuleSchemaForVersion(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coreBs, _ := backendBodySchema(coreSchema, "s3")
+	if coreBs.Attributes["bucket"].Description.Value == "changed" {
+		t.Fatal("expected backend block schema to remain unchanged")
+	}
+}
+
+func TestBackendConfigFileSchema_blocks(t *testing.T) {
+	bs, err := BackendConfigFileSchema("remote", version.Must(version.NewVersion("0.14.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspaces, ok := bs.Blocks["workspaces"]
+	if !ok {
+		t.Fatal("expected workspaces block")
+	}
+	if workspaces.MinItems != 0 {
+		t.Fatalf("expected workspaces block to be optional, MinItems: %d", workspaces.MinItems)
+	}
+}
+
+func TestBackendConfigFileSchema_unknownBackend(t *testing.T) {
+	testCases := []struct {
+		backendType   string
+		version       string
+		expectedError string
+	}{
+		{
+			"foobar",
+			"0.14.0",
+			`unknown backend type "foobar" for Terraform 0.14.0`,
+		},
+		{
+			"etcdv3",
+			"1.3.0",
+			`unknown backend type "etcdv3" for Terraform 1.3.0`,
+		},
+		{
+			"kubernetes",
+			"0.12.0",
+			`unknown backend type "kubernetes" for Terraform 0.12.0`,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := BackendConfigFileSchema(tc.backendType, version.Must(version.NewVersion(tc.version)))
+		if err == nil {
+			t.Fatalf("%s: expected error", tc.backendType)
+		}
+		if err.Error() != tc.expectedError {
+			t.Fatalf("%s: error mismatch.\nexpected: %q\ngiven: %q", tc.backendType, tc.expectedError, err.Error())
+		}
+	}
+}
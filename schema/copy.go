@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+// copyBodySchema returns a deep copy of the given body schema,
+// such that the copy can be modified without affecting the original,
+// which is typically a core schema shared by all merges.
+func copyBodySchema(bs *schema.BodySchema) *schema.BodySchema {
+	if bs == nil {
+		return nil
+	}
+
+	newBs := &schema.BodySchema{
+		AnyAttribute: copyAttributeSchema(bs.AnyAttribute),
+		IsDeprecated: bs.IsDeprecated,
+		Detail:       bs.Detail,
+		Description:  bs.Description,
+	}
+
+	if bs.Attributes != nil {
+		newBs.Attributes = make(map[string]*schema.AttributeSchema, len(bs.Attributes))
+		for name, attr := range bs.Attributes {
+			newBs.Attributes[name] = copyAttributeSchema(attr)
+		}
+	}
+
+	if bs.Blocks != nil {
+		newBs.Blocks = make(map[string]*schema.BlockSchema, len(bs.Blocks))
+		for name, block := range bs.Blocks {
+			newBs.Blocks[name] = copyBlockSchema(block)
+		}
+	}
+
+	return newBs
+}
+
+func copyBlockSchema(bs *schema.BlockSchema) *schema.BlockSchema {
+	if bs == nil {
+		return nil
+	}
+
+	newBs := &schema.BlockSchema{
+		Type:         bs.Type,
+		Body:         copyBodySchema(bs.Body),
+		Description:  bs.Description,
+		IsDeprecated: bs.IsDeprecated,
+		MinItems:     bs.MinItems,
+		MaxItems:     bs.MaxItems,
+	}
+
+	if bs.Labels != nil {
+		newBs.Labels = make([]*schema.LabelSchema, len(bs.Labels))
+		for i, label := range bs.Labels {
+			newBs.Labels[i] = copyLabelSchema(label)
+		}
+	}
+
+	if bs.DependentBody != nil {
+		newBs.DependentBody = make(map[schema.SchemaKey]*schema.BodySchema, len(bs.DependentBody))
+		for key, body := range bs.DependentBody {
+			newBs.DependentBody[key] = copyBodySchema(body)
+		}
+	}
+
+	return newBs
+}
+
+func copyLabelSchema(ls *schema.LabelSchema) *schema.LabelSchema {
+	if ls == nil {
+		return nil
+	}
+
+	newLs := *ls
+	return &newLs
+}
+
+func copyAttributeSchema(as *schema.AttributeSchema) *schema.AttributeSchema {
+	if as == nil {
+		return nil
+	}
+
+	newAs := *as
+	if as.ValueTypes != nil {
+		newAs.ValueTypes = make(schema.ValueTypes, len(as.ValueTypes))
+		copy(newAs.ValueTypes, as.ValueTypes)
+	}
+
+	return &newAs
+}
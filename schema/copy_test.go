@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCopyBodySchema_nil(t *testing.T) {
+	if bs := copyBodySchema(nil); bs != nil {
+		t.Fatalf("expected nil copy, given: %#v", bs)
+	}
+}
+
+func TestCopyBodySchema_equal(t *testing.T) {
+	versions := []string{"0.12.0", "0.13.0", "0.14.0", "0.15.0", "1.1.0", "1.2.0"}
+
+	for _, v := range versions {
+		coreSchema, err := CoreModuleSchemaForVersion(version.Must(version.NewVersion(v)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(coreSchema, copyBodySchema(coreSchema), ctydebug.CmpOptions); diff != "" {
+			t.Fatalf("%s: copy differs from original: %s", v, diff)
+		}
+	}
+}
+
+func TestCopyBodySchema_independent(t *testing.T) {
+	original := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"foo": {
+				ValueTypes: schema.ValueTypes{cty.String, cty.Number},
+				IsOptional: true,
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"bar": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type", IsDepKey: true},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"baz": {ValueType: cty.Bool, IsRequired: true},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					schema.NewSchemaKey(schema.DependencyKeys{
+						Labels: []schema.LabelDependent{
+							{Index: 0, Value: "first"},
+						},
+					}): {
+						Detail: "first",
+					},
+				},
+			},
+		},
+	}
+	expected := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"foo": {
+				ValueTypes: schema.ValueTypes{cty.String, cty.Number},
+				IsOptional: true,
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"bar": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type", IsDepKey: true},
+				},
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"baz": {ValueType: cty.Bool, IsRequired: true},
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					`{"labels":[{"index":0,"value":"first"}]}`: {
+						Detail: "first",
+					},
+				},
+			},
+		},
+	}
+
+	copied := copyBodySchema(original)
+	copied.Attributes["foo"].ValueTypes[0] = cty.Bool
+	copied.Attributes["new"] = &schema.AttributeSchema{ValueType: cty.String}
+	copied.Blocks["bar"].Labels[0].Name = "changed"
+	copied.Blocks["bar"].Body.Attributes["baz"].Description = lang.PlainText("changed")
+	copied.Blocks["bar"].DependentBody[`{"labels":[{"index":0,"value":"first"}]}`].Detail = "changed"
+	copied.Blocks["bar"].DependentBody[`{"labels":[{"index":0,"value":"second"}]}`] = &schema.BodySchema{}
+
+	if diff := cmp.Diff(expected, original, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("original schema was modified: %s", diff)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	mod_v0_12 "github.com/hashicorp/terraform-schema/internal/schema/0.12"
 	mod_v0_13 "github.com/hashicorp/terraform-schema/internal/schema/0.13"
 	mod_v0_14 "github.com/hashicorp/terraform-schema/internal/schema/0.14"
+	mod_v0_15 "github.com/hashicorp/terraform-schema/internal/schema/0.15"
+	mod_v1_0 "github.com/hashicorp/terraform-schema/internal/schema/1.0"
+	mod_v1_1 "github.com/hashicorp/terraform-schema/internal/schema/1.1"
+	mod_v1_2 "github.com/hashicorp/terraform-schema/internal/schema/1.2"
 	universal "github.com/hashicorp/terraform-schema/internal/schema/universal"
 )
 
@@ -15,6 +19,10 @@ var (
 	v0_12 = version.Must(version.NewVersion("0.12"))
 	v0_13 = version.Must(version.NewVersion("0.13"))
 	v0_14 = version.Must(version.NewVersion("0.14"))
+	v0_15 = version.Must(version.NewVersion("0.15"))
+	v1_0  = version.Must(version.NewVersion("1.0"))
+	v1_1  = version.Must(version.NewVersion("1.1"))
+	v1_2  = version.Must(version.NewVersion("1.2"))
 )
 
 // CoreModuleSchemaForVersion finds a module schema which is relevant
@@ -26,6 +34,18 @@ func CoreModuleSchemaForVersion(v *version.Version) (*schema.BodySchema, error)
 		return nil, fmt.Errorf("invalid version: %w", err)
 	}
 
+	if ver.GreaterThanOrEqual(v1_2) {
+		return mod_v1_2.ModuleSchema(ver), nil
+	}
+	if ver.GreaterThanOrEqual(v1_1) {
+		return mod_v1_1.ModuleSchema(ver), nil
+	}
+	if ver.GreaterThanOrEqual(v1_0) {
+		return mod_v1_0.ModuleSchema(ver), nil
+	}
+	if ver.GreaterThanOrEqual(v0_15) {
+		return mod_v0_15.ModuleSchema(ver), nil
+	}
 	if ver.GreaterThanOrEqual(v0_14) {
 		return mod_v0_14.ModuleSchema(ver), nil
 	}
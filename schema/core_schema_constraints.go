@@ -0,0 +1,299 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+// CoreSchemaSelection determines how a core schema
+// is chosen for a range of versions of Terraform
+type CoreSchemaSelection uint
+
+const (
+	// SelectNewest chooses the schema of the newest version
+	// of Terraform satisfying the constraints
+	SelectNewest CoreSchemaSelection = iota
+
+	// SelectCommon chooses a schema which only contains features
+	// available in all versions satisfying the constraints
+	SelectCommon
+)
+
+func (s CoreSchemaSelection) String() string {
+	switch s {
+	case SelectNewest:
+		return "newest"
+	case SelectCommon:
+		return "common"
+	}
+	return fmt.Sprintf("CoreSchemaSelection(%d)", uint(s))
+}
+
+// CoreSchemaChoice represents a core module schema chosen
+// for version constraints, along with the reasoning
+type CoreSchemaChoice struct {
+	Schema *schema.BodySchema
+
+	// Version is the version of Terraform whose schema
+	// was used as the base for Schema.
+	//
+	// It may be a version mentioned in the constraints (e.g. 1.4.0
+	// for >= 1.4), rather than a version in which the core schema
+	// is known to have changed, in which case the schema of the latest
+	// preceding known version is assumed, as noted in Explanation.
+	Version *version.Version
+
+	// Versions are versions of Terraform satisfying the constraints
+	// (oldest first) which were considered, one per each revision
+	// of the core schema
+	Versions []*version.Version
+
+	// ExcludedFeatures are features available in Version,
+	// but excluded from Schema because they are not available
+	// in some of the other versions (SelectCommon only)
+	ExcludedFeatures []*Feature
+
+	// Explanation is a human-readable explanation of the choice
+	Explanation string
+}
+
+// CoreModuleSchemaForConstraints finds a module schema which is relevant
+// for versions of Terraform satisfying the given constraints,
+// such as those declared via required_version.
+//
+// Empty constraints are satisfied by any version.
+// It will return error if no known version satisfies the constraints.
+func CoreModuleSchemaForConstraints(cs version.Constraints, sel CoreSchemaSelection) (*CoreSchemaChoice, error) {
+	versions := versionsSatisfying(cs)
+	if len(versions) == 0 {
+		return nil, unsatisfiableConstraintsErr{constraints: cs.String()}
+	}
+
+	oldest, newest := versions[0], versions[len(versions)-1]
+
+	satisfying := " (no version constraints)"
+	if len(cs) > 0 {
+		satisfying = fmt.Sprintf(" satisfying %q", cs.String())
+	}
+
+	switch sel {
+	case SelectNewest:
+		bs, err := CoreModuleSchemaForVersion(newest)
+		if err != nil {
+			return nil, err
+		}
+		return &CoreSchemaChoice{
+			Schema:   bs,
+			Version:  newest,
+			Versions: versions,
+			Explanation: fmt.Sprintf("Using schema of Terraform %s%s.",
+				newest, describeVersion(newest, "newest", satisfying)),
+		}, nil
+	case SelectCommon:
+		bs, err := CoreModuleSchemaForVersion(oldest)
+		if err != nil {
+			return nil, err
+		}
+		present, err := presentFeatures(versions)
+		if err != nil {
+			return nil, err
+		}
+
+		bs = copyBodySchema(bs)
+		excludedMap := make(map[string]*Feature, 0)
+		removedBy := make(map[string]*version.Version, 0)
+		filterBodySchema(bs, "", func(addr string) bool {
+			f, ok := CoreFeatureTable().Feature(addr)
+			if !ok {
+				return true
+			}
+			for i, v := range versions {
+				if !present[i][addr] {
+					excludedMap[addr] = f
+					removedBy[addr] = v
+					return false
+				}
+			}
+			return true
+		})
+		excluded := make([]*Feature, 0, len(excludedMap))
+		for _, f := range excludedMap {
+			excluded = append(excluded, f)
+		}
+		sort.Slice(excluded, func(i, j int) bool {
+			return excluded[i].Address < excluded[j].Address
+		})
+
+		explanation := fmt.Sprintf("Using schema of Terraform %s%s",
+			oldest, describeVersion(oldest, "oldest", satisfying))
+		if len(excluded) > 0 {
+			// features are grouped by the first version lacking them,
+			// which may differ from Removed in the feature table
+			// for features which were removed and reintroduced later
+			groups := make([]string, 0)
+			for _, v := range versions {
+				names := make([]string, 0)
+				for _, f := range excluded {
+					if removedBy[f.Address] == v {
+						names = append(names, f.Address)
+					}
+				}
+				if len(names) > 0 {
+					groups = append(groups, fmt.Sprintf("Terraform %s: %s", v, strings.Join(names, ", ")))
+				}
+			}
+			explanation += fmt.Sprintf(", without features removed by %s",
+				strings.Join(groups, "; "))
+		}
+
+		return &CoreSchemaChoice{
+			Schema:           bs,
+			Version:          oldest,
+			Versions:         versions,
+			ExcludedFeatures: excluded,
+			Explanation:      explanation + ".",
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown core schema selection: %s", sel)
+}
+
+// presentFeatures returns addresses of features present
+// in the core schema of each of the given versions
+func presentFeatures(versions []*version.Version) ([]map[string]bool, error) {
+	present := make([]map[string]bool, len(versions))
+	for i, v := range versions {
+		bs, err := CoreModuleSchemaForVersion(v)
+		if err != nil {
+			return nil, err
+		}
+		present[i] = make(map[string]bool, 0)
+		collectFeatures(bs, "", "", func(f *Feature) {
+			present[i][f.Address] = true
+		})
+	}
+	return present, nil
+}
+
+// versionsSatisfying returns known versions of Terraform (oldest first)
+// which satisfy the given constraints.
+//
+// Besides versions in which the core schema changed, versions
+// mentioned in the constraints (and the patch versions following them)
+// are considered, so that pinned versions (e.g. = 0.12.10)
+// or exclusive bounds (e.g. > 0.12.10) can be satisfied too.
+func versionsSatisfying(cs version.Constraints) []*version.Version {
+	candidates := make(map[string]*version.Version, 0)
+	for _, v := range coreSchemaVersions {
+		candidates[v.String()] = v
+	}
+	for _, c := range cs {
+		rawVersion := strings.TrimLeft(c.String(), "=!<>~ ")
+		v, err := version.NewVersion(rawVersion)
+		if err != nil || v.LessThan(v0_12) {
+			continue
+		}
+		segments := v.Segments64()
+		nextPatch, err := version.NewVersion(fmt.Sprintf("%d.%d.%d",
+			segments[0], segments[1], segments[2]+1))
+		if err != nil {
+			continue
+		}
+		candidates[v.String()] = v
+		candidates[nextPatch.String()] = nextPatch
+	}
+
+	// pick one version per revision of the core schema, preferring
+	// the version which introduced the revision, as other versions
+	// of the same revision share the same schema
+	revisions := make(map[string]*version.Version, 0)
+	for _, v := range candidates {
+		if !cs.Check(v) {
+			continue
+		}
+		rev := schemaRevision(v).String()
+		if existing, ok := revisions[rev]; ok && existing.LessThan(v) {
+			continue
+		}
+		revisions[rev] = v
+	}
+
+	versions := make([]*version.Version, 0, len(revisions))
+	for _, v := range revisions {
+		versions = append(versions, v)
+	}
+	sort.Sort(version.Collection(versions))
+
+	return versions
+}
+
+// describeVersion describes how the given version was chosen,
+// noting whether it is a known version in which the core schema
+// changed, or one extrapolated from the constraints
+func describeVersion(v *version.Version, adjective, satisfying string) string {
+	rev := schemaRevision(v)
+	if rev.Equal(v) {
+		return fmt.Sprintf(", the %s known version%s", adjective, satisfying)
+	}
+	return fmt.Sprintf(", the %s version%s (extrapolated from the constraints, "+
+		"assuming the schema of Terraform %s, the latest known version preceding it)",
+		adjective, satisfying, rev)
+}
+
+// schemaRevision returns the version in which the core schema
+// of the given version was introduced
+func schemaRevision(v *version.Version) *version.Version {
+	if sv, err := semVer(v); err == nil {
+		v = sv
+	}
+	rev := coreSchemaVersions[0]
+	for _, sv := range coreSchemaVersions {
+		if !sv.GreaterThan(v) {
+			rev = sv
+		}
+	}
+	return rev
+}
+
+// filterBodySchema removes attributes, blocks and dependent bodies
+// whose feature address is rejected by the keep function
+// from the given body schema (in place)
+func filterBodySchema(bs *schema.BodySchema, addr string, keep func(string) bool) {
+	if bs == nil {
+		return
+	}
+
+	for name := range bs.Attributes {
+		if !keep(FeatureAddress(addr, name)) {
+			delete(bs.Attributes, name)
+		}
+	}
+
+	for bType, block := range bs.Blocks {
+		blockAddr := FeatureAddress(addr, bType)
+		if !keep(blockAddr) {
+			delete(bs.Blocks, bType)
+			continue
+		}
+
+		filterBodySchema(block.Body, blockAddr, keep)
+		for key, depBody := range block.DependentBody {
+			label, ok := dependentBodyLabel(key)
+			if !ok {
+				// bodies keyed on attributes are part of the block body
+				filterBodySchema(depBody, blockAddr, keep)
+				continue
+			}
+			depAddr := DependentBodyFeatureAddress(blockAddr, label)
+			if !keep(depAddr) {
+				delete(block.DependentBody, key)
+				continue
+			}
+			filterBodySchema(depBody, depAddr, keep)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+func TestCoreModuleSchemaForConstraints(t *testing.T) {
+	testCases := []struct {
+		constraints              string
+		selection                CoreSchemaSelection
+		expectedVersion          string
+		expectedVersions         []string
+		expectedExcludedFeatures []string
+		expectedExplanation      string
+	}{
+		{
+			"",
+			SelectNewest,
+			"1.3.0",
+			[]string{"0.12.0", "0.12.2", "0.12.6", "0.12.18", "0.12.20", "0.13.0", "0.14.0",
+				"0.15.0", "1.0.0", "1.1.0", "1.2.0", "1.2.3", "1.3.0"},
+			[]string{},
+			"Using schema of Terraform 1.3.0, the newest known version (no version constraints).",
+		},
+		{
+			">= 0.13, < 0.15",
+			SelectNewest,
+			"0.14.0",
+			[]string{"0.13.0", "0.14.0"},
+			[]string{},
+			`Using schema of Terraform 0.14.0, the newest known version satisfying ">= 0.13, < 0.15".`,
+		},
+		{
+			">= 0.13, < 0.15",
+			SelectCommon,
+			"0.13.0",
+			[]string{"0.13.0", "0.14.0"},
+			[]string{},
+			`Using schema of Terraform 0.13.0, the oldest known version satisfying ">= 0.13, < 0.15".`,
+		},
+		{
+			">= 0.14, < 1.0",
+			SelectCommon,
+			"0.14.0",
+			[]string{"0.14.0", "0.15.0"},
+			[]string{
+				`resource.provisioner["chef"]`,
+				`resource.provisioner["habitat"]`,
+				`resource.provisioner["puppet"]`,
+				`resource.provisioner["salt-masterless"]`,
+				`terraform.backend["kubernetes"].load_config_file`,
+			},
+			`Using schema of Terraform 0.14.0, the oldest known version satisfying ">= 0.14, < 1.0", ` +
+				`without features removed by Terraform 0.15.0: resource.provisioner["chef"], ` +
+				`resource.provisioner["habitat"], resource.provisioner["puppet"], ` +
+				`resource.provisioner["salt-masterless"], ` +
+				`terraform.backend["kubernetes"].load_config_file.`,
+		},
+		{
+			">= 0.14",
+			SelectCommon,
+			"0.14.0",
+			[]string{"0.14.0", "0.15.0", "1.0.0", "1.1.0", "1.2.0", "1.2.3", "1.3.0"},
+			[]string{
+				`resource.provisioner["chef"]`,
+				`resource.provisioner["habitat"]`,
+				`resource.provisioner["puppet"]`,
+				`resource.provisioner["salt-masterless"]`,
+				`terraform.backend["artifactory"]`,
+				`terraform.backend["etcd"]`,
+				`terraform.backend["etcdv3"]`,
+				`terraform.backend["kubernetes"].load_config_file`,
+				`terraform.backend["manta"]`,
+				`terraform.backend["swift"]`,
+			},
+			`Using schema of Terraform 0.14.0, the oldest known version satisfying ">= 0.14", ` +
+				`without features removed by Terraform 0.15.0: resource.provisioner["chef"], ` +
+				`resource.provisioner["habitat"], resource.provisioner["puppet"], ` +
+				`resource.provisioner["salt-masterless"], ` +
+				`terraform.backend["kubernetes"].load_config_file; ` +
+				`Terraform 1.3.0: terraform.backend["artifactory"], terraform.backend["etcd"], ` +
+				`terraform.backend["etcdv3"], terraform.backend["manta"], terraform.backend["swift"].`,
+		},
+		{
+			"= 0.12.10",
+			SelectNewest,
+			"0.12.10",
+			[]string{"0.12.10"},
+			[]string{},
+			`Using schema of Terraform 0.12.10, the newest version satisfying "= 0.12.10" ` +
+				`(extrapolated from the constraints, assuming the schema of Terraform 0.12.6, ` +
+				`the latest known version preceding it).`,
+		},
+		{
+			"> 0.12.10, < 0.13",
+			SelectCommon,
+			"0.12.11",
+			[]string{"0.12.11", "0.12.18", "0.12.20"},
+			[]string{},
+			`Using schema of Terraform 0.12.11, the oldest version satisfying "> 0.12.10, < 0.13" ` +
+				`(extrapolated from the constraints, assuming the schema of Terraform 0.12.6, ` +
+				`the latest known version preceding it).`,
+		},
+		{
+			">= 1.4",
+			SelectNewest,
+			"1.4.0",
+			[]string{"1.4.0"},
+			[]string{},
+			`Using schema of Terraform 1.4.0, the newest version satisfying ">= 1.4" ` +
+				`(extrapolated from the constraints, assuming the schema of Terraform 1.3.0, ` +
+				`the latest known version preceding it).`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s-%s", i, tc.constraints, tc.selection), func(t *testing.T) {
+			cs, err := version.NewConstraint(tc.constraints)
+			if tc.constraints == "" {
+				cs, err = version.Constraints{}, nil
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			choice, err := CoreModuleSchemaForConstraints(cs, tc.selection)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := choice.Schema.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			if choice.Version.String() != tc.expectedVersion {
+				t.Fatalf("version mismatch.\nexpected: %s\ngiven: %s",
+					tc.expectedVersion, choice.Version)
+			}
+
+			versions := make([]string, len(choice.Versions))
+			for i, v := range choice.Versions {
+				versions[i] = v.String()
+			}
+			if diff := cmp.Diff(tc.expectedVersions, versions); diff != "" {
+				t.Fatalf("versions mismatch: %s", diff)
+			}
+
+			excluded := make([]string, len(choice.ExcludedFeatures))
+			for i, f := range choice.ExcludedFeatures {
+				excluded[i] = f.Address
+			}
+			if diff := cmp.Diff(tc.expectedExcludedFeatures, excluded); diff != "" {
+				t.Fatalf("excluded features mismatch: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedExplanation, choice.Explanation); diff != "" {
+				t.Fatalf("explanation mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCoreModuleSchemaForConstraints_common(t *testing.T) {
+	cs, err := version.NewConstraint(">= 0.14, < 1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	choice, err := CoreModuleSchemaForConstraints(cs, SelectCommon)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provisioner := choice.Schema.Blocks["resource"].Body.Blocks["provisioner"]
+	if hasProvisioner(provisioner, "chef") {
+		t.Fatal("expected chef provisioner to be excluded")
+	}
+	if !hasProvisioner(provisioner, "local-exec") {
+		t.Fatal("expected local-exec provisioner to be present")
+	}
+
+	// the shared core schema must not be modified
+	bs, err := CoreModuleSchemaForVersion(choice.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasProvisioner(bs.Blocks["resource"].Body.Blocks["provisioner"], "chef") {
+		t.Fatal("expected chef provisioner in the original schema")
+	}
+}
+
+func hasProvisioner(provisioner *schema.BlockSchema, name string) bool {
+	_, ok := provisioner.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: name},
+		},
+	})
+	return ok
+}
+
+func TestCoreModuleSchemaForConstraints_unsatisfiable(t *testing.T) {
+	cs, err := version.NewConstraint("< 0.12")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = CoreModuleSchemaForConstraints(cs, SelectNewest)
+	if err == nil {
+		t.Fatal("expected error for unsatisfiable constraints")
+	}
+	expectedErr := `no known version of Terraform satisfies "< 0.12"`
+	if err.Error() != expectedErr {
+		t.Fatalf("error mismatch.\nexpected: %s\ngiven: %s", expectedErr, err)
+	}
+}
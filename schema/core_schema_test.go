@@ -11,7 +11,12 @@ import (
 	mod_v0_12 "github.com/hashicorp/terraform-schema/internal/schema/0.12"
 	mod_v0_13 "github.com/hashicorp/terraform-schema/internal/schema/0.13"
 	mod_v0_14 "github.com/hashicorp/terraform-schema/internal/schema/0.14"
+	mod_v0_15 "github.com/hashicorp/terraform-schema/internal/schema/0.15"
+	mod_v1_0 "github.com/hashicorp/terraform-schema/internal/schema/1.0"
+	mod_v1_1 "github.com/hashicorp/terraform-schema/internal/schema/1.1"
+	mod_v1_2 "github.com/hashicorp/terraform-schema/internal/schema/1.2"
 	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestCoreModuleSchemaForVersion_tooOld(t *testing.T) {
@@ -35,6 +40,14 @@ func TestCoreModuleSchemaForVersion_validate(t *testing.T) {
 		"0.13.0",
 		"0.14.0-beta2",
 		"0.14.0",
+		"0.15.0-beta1",
+		"0.15.0",
+		"1.0.0",
+		"1.1.0-alpha20211006",
+		"1.1.0",
+		"1.2.0-rc1",
+		"1.2.0",
+		"1.3.5",
 	}
 
 	for _, v := range versions {
@@ -75,6 +88,30 @@ func TestCoreModuleSchemaForVersion_matching(t *testing.T) {
 			version.Must(version.NewVersion("0.14.0-beta2")),
 			mod_v0_14.ModuleSchema,
 		},
+		{
+			version.Must(version.NewVersion("0.15.0-beta1")),
+			mod_v0_15.ModuleSchema,
+		},
+		{
+			version.Must(version.NewVersion("0.15.5")),
+			mod_v0_15.ModuleSchema,
+		},
+		{
+			version.Must(version.NewVersion("1.0.0")),
+			mod_v1_0.ModuleSchema,
+		},
+		{
+			version.Must(version.NewVersion("1.1.0-alpha20211006")),
+			mod_v1_1.ModuleSchema,
+		},
+		{
+			version.Must(version.NewVersion("1.2.0")),
+			mod_v1_2.ModuleSchema,
+		},
+		{
+			version.Must(version.NewVersion("1.3.5")),
+			mod_v1_2.ModuleSchema,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -93,3 +130,143 @@ func TestCoreModuleSchemaForVersion_matching(t *testing.T) {
 }
 
 type versionedBodySchema func(*version.Version) *schema.BodySchema
+
+func TestCoreModuleSchemaForVersion_provisioners(t *testing.T) {
+	testCases := []struct {
+		version              string
+		expectedProvisioners []string
+	}{
+		{
+			"0.12.0",
+			[]string{"chef", "file", "habitat", "local-exec", "remote-exec", "salt-masterless"},
+		},
+		{
+			"0.12.2",
+			[]string{"chef", "file", "habitat", "local-exec", "puppet", "remote-exec", "salt-masterless"},
+		},
+		{
+			"0.14.0",
+			[]string{"chef", "file", "habitat", "local-exec", "puppet", "remote-exec", "salt-masterless"},
+		},
+		{
+			"0.15.0",
+			[]string{"file", "local-exec", "remote-exec"},
+		},
+		{
+			"1.2.0",
+			[]string{"file", "local-exec", "remote-exec"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.version), func(t *testing.T) {
+			bodySchema, err := CoreModuleSchemaForVersion(version.Must(version.NewVersion(tc.version)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			provisioner, ok := bodySchema.Blocks["resource"].Body.Blocks["provisioner"]
+			if !ok {
+				t.Fatal("expected provisioner block in resource")
+			}
+
+			provisioners := make([]string, 0)
+			for _, name := range allProvisioners {
+				_, ok := provisioner.DependentBodySchema(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: name},
+					},
+				})
+				if ok {
+					provisioners = append(provisioners, name)
+				}
+			}
+
+			if diff := cmp.Diff(tc.expectedProvisioners, provisioners); diff != "" {
+				t.Fatalf("provisioners mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+var allProvisioners = []string{
+	"chef",
+	"file",
+	"habitat",
+	"local-exec",
+	"puppet",
+	"remote-exec",
+	"salt-masterless",
+}
+
+func TestCoreModuleSchemaForVersion_connection(t *testing.T) {
+	bodySchema, err := CoreModuleSchemaForVersion(version.Must(version.NewVersion("0.14.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceBody := bodySchema.Blocks["resource"].Body
+	connBlocks := map[string]*schema.BlockSchema{
+		"resource":    resourceBody.Blocks["connection"],
+		"provisioner": resourceBody.Blocks["provisioner"].Body.Blocks["connection"],
+	}
+
+	expectedAttributes := map[string]string{
+		"ssh":   "bastion_host",
+		"winrm": "https",
+	}
+
+	for parent, connBlock := range connBlocks {
+		for connType, attrName := range expectedAttributes {
+			body, ok := connBlock.DependentBodySchema(schema.DependencyKeys{
+				Attributes: []schema.AttributeDependent{
+					{
+						Name: "type",
+						Expr: schema.ExpressionValue{
+							Static: cty.StringVal(connType),
+						},
+					},
+				},
+			})
+			if !ok {
+				t.Fatalf("%s: expected dependent body for %q connection", parent, connType)
+			}
+			if _, ok := body.Attributes[attrName]; !ok {
+				t.Fatalf("%s: expected %q attribute for %q connection", parent, attrName, connType)
+			}
+		}
+
+		// type defaults to ssh
+		body, ok := connBlock.DependentBodySchema(schema.DependencyKeys{})
+		if !ok {
+			t.Fatalf("%s: expected dependent body for connection without type", parent)
+		}
+		if _, ok := body.Attributes["bastion_host"]; !ok {
+			t.Fatalf("%s: expected ssh attributes for connection without type", parent)
+		}
+	}
+}
+
+func TestCoreModuleSchemaForVersion_backends(t *testing.T) {
+	versions := []string{"0.12.0", "0.13.0", "0.15.0", "1.1.0"}
+
+	for _, v := range versions {
+		bodySchema, err := CoreModuleSchemaForVersion(version.Must(version.NewVersion(v)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		backend := bodySchema.Blocks["terraform"].Body.Blocks["backend"]
+		body, ok := backend.DependentBodySchema(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: "s3"},
+			},
+		})
+		if !ok {
+			t.Fatalf("%s: expected dependent body for s3 backend", v)
+		}
+		if _, ok := body.Attributes["bucket"]; !ok {
+			t.Fatalf("%s: expected bucket attribute in s3 backend", v)
+		}
+	}
+}
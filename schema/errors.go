@@ -1,7 +1,26 @@
 package schema
 
+import "fmt"
+
 type coreSchemaRequiredErr struct{}
 
 func (e coreSchemaRequiredErr) Error() string {
 	return "core schema required (none provided)"
 }
+
+type unknownBackendErr struct {
+	backendType string
+	version     string
+}
+
+func (e unknownBackendErr) Error() string {
+	return fmt.Sprintf("unknown backend type %q for Terraform %s", e.backendType, e.version)
+}
+
+type unsatisfiableConstraintsErr struct {
+	constraints string
+}
+
+func (e unsatisfiableConstraintsErr) Error() string {
+	return fmt.Sprintf("no known version of Terraform satisfies %q", e.constraints)
+}
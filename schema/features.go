@@ -0,0 +1,274 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+// coreSchemaVersions lists versions of Terraform in which
+// the core module schema changed, i.e. which introduced
+// or removed any attributes, blocks or dependent bodies
+var coreSchemaVersions = []*version.Version{
+	version.Must(version.NewVersion("0.12.0")),
+	version.Must(version.NewVersion("0.12.2")),
+	version.Must(version.NewVersion("0.12.6")),
+	version.Must(version.NewVersion("0.12.18")),
+	version.Must(version.NewVersion("0.12.20")),
+	version.Must(version.NewVersion("0.13.0")),
+	version.Must(version.NewVersion("0.14.0")),
+	version.Must(version.NewVersion("0.15.0")),
+	version.Must(version.NewVersion("1.0.0")),
+	version.Must(version.NewVersion("1.1.0")),
+	version.Must(version.NewVersion("1.2.0")),
+	version.Must(version.NewVersion("1.2.3")),
+	version.Must(version.NewVersion("1.3.0")),
+}
+
+type FeatureKind uint
+
+const (
+	FeatureAttribute FeatureKind = iota
+	FeatureBlock
+	// FeatureDependentBody represents a dependent body
+	// keyed on a label, such as a provisioner or a backend
+	FeatureDependentBody
+)
+
+// Feature represents an attribute, block or dependent body
+// of the core module schema along with versions of Terraform
+// in which it is available
+type Feature struct {
+	Kind FeatureKind
+
+	// Address identifies the feature within the module,
+	// e.g. module.for_each or terraform.backend["etcd"]
+	Address string
+
+	// Parent is the address of the body the feature belongs to,
+	// which is empty for the root module body
+	Parent string
+
+	// Name is the attribute name, block type, or the label
+	// value for dependent bodies
+	Name string
+
+	// LabelNames are names of labels of the block
+	LabelNames []string
+
+	// Description is a human-readable name of the feature,
+	// e.g. "`for_each` on module blocks"
+	Description string
+
+	// Introduced is the first version of Terraform
+	// in which the feature is available
+	Introduced *version.Version
+
+	// Removed is the first version of Terraform in which
+	// the feature is no longer available, or nil
+	Removed *version.Version
+}
+
+// IsAvailable returns whether the feature
+// is available in the given version of Terraform
+func (f *Feature) IsAvailable(v *version.Version) bool {
+	v, err := semVer(v)
+	if err != nil {
+		return false
+	}
+	if v.LessThan(f.Introduced) {
+		return false
+	}
+	return f.Removed == nil || v.LessThan(f.Removed)
+}
+
+// UnavailableDetail explains why the feature is not available
+// in the given version of Terraform, or returns an empty string
+// if the feature is available
+func (f *Feature) UnavailableDetail(v *version.Version) string {
+	v, err := semVer(v)
+	if err != nil {
+		return ""
+	}
+	if v.LessThan(f.Introduced) {
+		return fmt.Sprintf("%s requires Terraform >= %s", f.Description, f.Introduced)
+	}
+	if f.Removed != nil && !v.LessThan(f.Removed) {
+		return fmt.Sprintf("%s was removed in Terraform %s", f.Description, f.Removed)
+	}
+	return ""
+}
+
+// FeatureTable describes which attributes, blocks and dependent bodies
+// of the core module schema are available in which versions of Terraform
+type FeatureTable struct {
+	features map[string]*Feature
+}
+
+var (
+	coreFeatureTable     *FeatureTable
+	coreFeatureTableOnce sync.Once
+)
+
+// CoreFeatureTable returns the feature table built
+// from core module schemas of all known versions.
+//
+// The table is shared and must not be modified.
+func CoreFeatureTable() *FeatureTable {
+	coreFeatureTableOnce.Do(func() {
+		coreFeatureTable = buildFeatureTable(coreSchemaVersions)
+	})
+	return coreFeatureTable
+}
+
+func buildFeatureTable(versions []*version.Version) *FeatureTable {
+	ft := &FeatureTable{
+		features: make(map[string]*Feature, 0),
+	}
+
+	for _, v := range versions {
+		bs, err := CoreModuleSchemaForVersion(v)
+		if err != nil {
+			continue
+		}
+
+		present := make(map[string]bool, 0)
+		collectFeatures(bs, "", "", func(f *Feature) {
+			present[f.Address] = true
+			if _, ok := ft.features[f.Address]; !ok {
+				f.Introduced = v
+				ft.features[f.Address] = f
+			}
+		})
+
+		for addr, f := range ft.features {
+			if !present[addr] && f.Removed == nil {
+				f.Removed = v
+			}
+		}
+	}
+
+	return ft
+}
+
+// Feature returns the feature of the given address
+func (ft *FeatureTable) Feature(address string) (*Feature, bool) {
+	f, ok := ft.features[address]
+	return f, ok
+}
+
+// Features returns all features sorted by address
+func (ft *FeatureTable) Features() []*Feature {
+	features := make([]*Feature, 0, len(ft.features))
+	for _, f := range ft.features {
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Address < features[j].Address
+	})
+	return features
+}
+
+// collectFeatures walks the body schema and reports any attributes,
+// blocks and label-keyed dependent bodies found.
+//
+// bodyNoun describes the block which the body belongs to
+// (e.g. "module blocks"), and is empty for the root body.
+func collectFeatures(bs *schema.BodySchema, addr, bodyNoun string, fn func(*Feature)) {
+	if bs == nil {
+		return
+	}
+
+	for name := range bs.Attributes {
+		desc := fmt.Sprintf("`%s`", name)
+		if bodyNoun != "" {
+			desc = fmt.Sprintf("`%s` on %s", name, bodyNoun)
+		}
+		fn(&Feature{
+			Kind:        FeatureAttribute,
+			Address:     FeatureAddress(addr, name),
+			Parent:      addr,
+			Name:        name,
+			Description: desc,
+		})
+	}
+
+	for bType, block := range bs.Blocks {
+		blockAddr := FeatureAddress(addr, bType)
+
+		labelNames := make([]string, len(block.Labels))
+		for i, label := range block.Labels {
+			labelNames[i] = label.Name
+		}
+
+		desc := fmt.Sprintf("`%s` block", bType)
+		if bodyNoun != "" {
+			desc = fmt.Sprintf("`%s` block in %s", bType, bodyNoun)
+		}
+		fn(&Feature{
+			Kind:        FeatureBlock,
+			Address:     blockAddr,
+			Parent:      addr,
+			Name:        bType,
+			LabelNames:  labelNames,
+			Description: desc,
+		})
+
+		blockNoun := fmt.Sprintf("%s blocks", bType)
+		collectFeatures(block.Body, blockAddr, blockNoun, fn)
+
+		for key, depBody := range block.DependentBody {
+			label, ok := dependentBodyLabel(key)
+			if !ok {
+				// bodies keyed on attributes (e.g. connection type)
+				// are treated as part of the block body
+				collectFeatures(depBody, blockAddr, blockNoun, fn)
+				continue
+			}
+
+			depAddr := DependentBodyFeatureAddress(blockAddr, label)
+			fn(&Feature{
+				Kind:        FeatureDependentBody,
+				Address:     depAddr,
+				Parent:      blockAddr,
+				Name:        label,
+				Description: fmt.Sprintf("`%s` %s", label, bType),
+			})
+			collectFeatures(depBody, depAddr, fmt.Sprintf("`%s` %s blocks", label, bType), fn)
+		}
+	}
+}
+
+// FeatureAddress returns the address of an attribute or block
+// of the given name within the body of the given address
+func FeatureAddress(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// DependentBodyFeatureAddress returns the address of a dependent body
+// keyed on the given label of the block of the given address,
+// e.g. terraform.backend["etcd"]
+func DependentBodyFeatureAddress(blockAddr, label string) string {
+	return fmt.Sprintf("%s[%q]", blockAddr, label)
+}
+
+// dependentBodyLabel returns the label value of the given key,
+// if the key consists of a single label only
+func dependentBodyLabel(key schema.SchemaKey) (string, bool) {
+	var keys struct {
+		Labels     []schema.LabelDependent `json:"labels"`
+		Attributes []json.RawMessage       `json:"attrs"`
+	}
+	err := json.Unmarshal([]byte(key), &keys)
+	if err != nil || len(keys.Labels) != 1 || len(keys.Attributes) > 0 {
+		return "", false
+	}
+	return keys.Labels[0].Value, true
+}
@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestCoreFeatureTable(t *testing.T) {
+	testCases := []struct {
+		address            string
+		expectedIntroduced string
+		expectedRemoved    string
+	}{
+		{"module.for_each", "0.13.0", ""},
+		{"resource.for_each", "0.12.6", ""},
+		{"variable.validation", "0.12.20", ""},
+		{"variable.sensitive", "0.14.0", ""},
+		{"moved", "1.1.0", ""},
+		{"terraform.cloud.workspaces", "1.1.0", ""},
+		{"resource.lifecycle.precondition", "1.2.0", ""},
+		{`resource.provisioner["chef"]`, "0.12.0", "0.15.0"},
+		{`resource.provisioner["puppet"]`, "0.12.2", "0.15.0"},
+		{`terraform.backend["kubernetes"].load_config_file`, "0.13.0", "0.15.0"},
+		{`terraform.backend["etcd"]`, "0.12.0", "1.3.0"},
+		{`terraform.backend["etcdv3"]`, "0.12.0", "1.3.0"},
+		{"resource.count", "0.12.0", ""},
+	}
+
+	ft := CoreFeatureTable()
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.address), func(t *testing.T) {
+			f, ok := ft.Feature(tc.address)
+			if !ok {
+				t.Fatalf("feature %q not found", tc.address)
+			}
+			if f.Introduced.String() != tc.expectedIntroduced {
+				t.Fatalf("introduced version mismatch.\nexpected: %s\ngiven: %s",
+					tc.expectedIntroduced, f.Introduced)
+			}
+			removed := ""
+			if f.Removed != nil {
+				removed = f.Removed.String()
+			}
+			if removed != tc.expectedRemoved {
+				t.Fatalf("removed version mismatch.\nexpected: %q\ngiven: %q",
+					tc.expectedRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestFeature_UnavailableDetail(t *testing.T) {
+	testCases := []struct {
+		address        string
+		version        string
+		expectedDetail string
+	}{
+		{
+			"module.for_each",
+			"0.12.31",
+			"`for_each` on module blocks requires Terraform >= 0.13.0",
+		},
+		{
+			"module.for_each",
+			"0.13.0-beta1",
+			"",
+		},
+		{
+			"variable.validation",
+			"0.12.19",
+			"`validation` block in variable blocks requires Terraform >= 0.12.20",
+		},
+		{
+			"moved",
+			"1.0.11",
+			"`moved` block requires Terraform >= 1.1.0",
+		},
+		{
+			`resource.provisioner["chef"]`,
+			"1.0.0",
+			"`chef` provisioner was removed in Terraform 0.15.0",
+		},
+		{
+			`terraform.backend["s3"].assume_role_tags`,
+			"0.13.0",
+			"`assume_role_tags` on `s3` backend blocks requires Terraform >= 0.14.0",
+		},
+	}
+
+	ft := CoreFeatureTable()
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.address), func(t *testing.T) {
+			f, ok := ft.Feature(tc.address)
+			if !ok {
+				t.Fatalf("feature %q not found", tc.address)
+			}
+			v := version.Must(version.NewVersion(tc.version))
+			detail := f.UnavailableDetail(v)
+			if detail != tc.expectedDetail {
+				t.Fatalf("detail mismatch.\nexpected: %q\ngiven: %q", tc.expectedDetail, detail)
+			}
+			if f.IsAvailable(v) != (tc.expectedDetail == "") {
+				t.Fatalf("availability mismatch for %s", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/lockfile"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var lockFileSchema = &schema.BodySchema{
+	Blocks: map[string]*schema.BlockSchema{
+		"provider": {
+			Labels: []*schema.LabelSchema{
+				{
+					Name:        "source_addr",
+					Description: lang.Markdown("Fully-qualified provider source address, e.g. `registry.terraform.io/hashicorp/aws`"),
+				},
+			},
+			Description: lang.PlainText("Provider selected by Terraform, along with checksums to verify it"),
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"version": {
+						ValueType:   cty.String,
+						IsRequired:  true,
+						Description: lang.Markdown("Exact version of the provider selected, e.g. `3.27.0`"),
+					},
+					"constraints": {
+						ValueType:  cty.String,
+						IsOptional: true,
+						Description: lang.Markdown("Version constraints declared in configuration " +
+							"at the time the version was selected, e.g. `~> 3.27`"),
+					},
+					"hashes": {
+						ValueType:  cty.List(cty.String),
+						IsOptional: true,
+						Description: lang.Markdown("Checksums of the provider packages considered valid, " +
+							"e.g. `h1:...` or `zh:...`"),
+					},
+				},
+			},
+		},
+	},
+}
+
+// LockFileSchema returns the body schema of the dependency
+// lock file (.terraform.lock.hcl) used since Terraform 0.14
+func LockFileSchema() *schema.BodySchema {
+	return copyBodySchema(lockFileSchema)
+}
+
+// ProviderVersionsFromLockFile returns versions of providers
+// locked in the given dependency lock file, keyed by provider
+// source address, as accepted by SetProviderVersions.
+//
+// Addresses are returned as (fully-qualified) strings,
+// such as registry.terraform.io/hashicorp/aws, since the provider
+// address type is internal and all other APIs accepting
+// provider addresses take them as strings.
+func ProviderVersionsFromLockFile(f *hcl.File) (map[string]*version.Version, hcl.Diagnostics) {
+	lockedVersions, diags := lockfile.DecodeProviderVersions(f)
+
+	versions := make(map[string]*version.Version, len(lockedVersions))
+	for addr, v := range lockedVersions {
+		versions[addr.String()] = v
+	}
+
+	return versions, diags
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestLockFileSchema_validate(t *testing.T) {
+	if err := LockFileSchema().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProviderVersionsFromLockFile(t *testing.T) {
+	src := `provider "registry.terraform.io/hashicorp/aws" {
+  version     = "3.27.0"
+  constraints = "~> 3.27"
+  hashes = [
+    "h1:SvMIoNmE9ASd4ceDzCHB6FlPKkXK0d5yfr3JwxPTpjE=",
+  ]
+}
+
+provider "example.com/mycorp/mycloud" {
+  version = "1.2.0"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), ".terraform.lock.hcl", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	versions, diags := ProviderVersionsFromLockFile(f)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedVersions := map[string]*version.Version{
+		"registry.terraform.io/hashicorp/aws": version.Must(version.NewVersion("3.27.0")),
+		"example.com/mycorp/mycloud":          version.Must(version.NewVersion("1.2.0")),
+	}
+	if diff := cmp.Diff(expectedVersions, versions); diff != "" {
+		t.Fatalf("versions mismatch: %s", diff)
+	}
+
+	sm := NewSchemaMerger(nil)
+	if err := sm.SetProviderVersions(versions); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-schema/internal/module"
+	"github.com/hashicorp/terraform-schema/internal/refdecoder"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// mergeModuleInputs adds schemas for inputs of modules called
+// via local source, based on variables of those modules,
+// as dependent bodies of the module block keyed on source
+func (m *SchemaMerger) mergeModuleInputs(mergedSchema *schema.BodySchema) {
+	moduleBlock, ok := mergedSchema.Blocks["module"]
+	if !ok {
+		return
+	}
+
+	calls, _ := refdecoder.DecodeModuleCalls(m.parsedFiles)
+	for _, call := range calls {
+		if !module.IsLocalSource(call.Source) {
+			continue
+		}
+
+		files, err := module.ParseFiles(filepath.Join(m.modulePath, filepath.FromSlash(call.Source)))
+		if err != nil {
+			// module may not exist (yet)
+			continue
+		}
+		variables, _ := module.DecodeVariablesForVersion(files, m.coreVersion)
+
+		if moduleBlock.DependentBody == nil {
+			moduleBlock.DependentBody = make(map[schema.SchemaKey]*schema.BodySchema)
+		}
+		moduleBlock.DependentBody[schema.NewSchemaKey(moduleSourceDependencyKeys(call.Source))] = moduleInputsBodySchema(call.Source, variables)
+	}
+}
+
+func moduleSourceDependencyKeys(source string) schema.DependencyKeys {
+	return schema.DependencyKeys{
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "source",
+				Expr: schema.ExpressionValue{
+					Static: cty.StringVal(source),
+				},
+			},
+		},
+	}
+}
+
+func moduleInputsBodySchema(source string, variables map[string]*module.Variable) *schema.BodySchema {
+	bs := &schema.BodySchema{
+		Attributes: make(map[string]*schema.AttributeSchema, len(variables)),
+		Detail:     source,
+	}
+
+	for name, v := range variables {
+		bs.Attributes[name] = &schema.AttributeSchema{
+			Description: variableDescription(v),
+			IsRequired:  v.IsRequired,
+			IsOptional:  !v.IsRequired,
+			ValueType:   v.Type,
+		}
+	}
+
+	return bs
+}
+
+func variableDescription(v *module.Variable) lang.MarkupContent {
+	parts := make([]string, 0)
+	if v.Description != "" {
+		parts = append(parts, v.Description)
+	}
+	if !v.IsRequired && v.Default.IsWhollyKnown() {
+		if v.IsSensitive {
+			parts = append(parts, "Default: (sensitive)")
+		} else {
+			defaultVal := strings.TrimSpace(string(hclwrite.TokensForValue(v.Default).Bytes()))
+			if strings.Contains(defaultVal, "\n") {
+				parts = append(parts, fmt.Sprintf("Default:\n```\n%s\n```", defaultVal))
+			} else {
+				parts = append(parts, fmt.Sprintf("Default: `%s`", defaultVal))
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return lang.MarkupContent{}
+	}
+	return lang.Markdown(strings.Join(parts, "\n\n"))
+}
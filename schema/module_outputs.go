@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/terraform-schema/internal/module"
+	"github.com/hashicorp/terraform-schema/internal/refdecoder"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleCallOutputs describes outputs of a single module call,
+// i.e. targets of module.NAME.OUTPUT references
+type ModuleCallOutputs struct {
+	// Name is the name of the module call (module block label)
+	Name   string
+	Source string
+
+	// HasCount and HasForEach indicate that outputs need to be referenced
+	// via a particular instance, e.g. module.NAME[0].OUTPUT
+	// or module.NAME["key"].OUTPUT respectively
+	HasCount   bool
+	HasForEach bool
+
+	Outputs map[string]*ModuleOutput
+}
+
+// ModuleOutput describes a single output of a called module
+type ModuleOutput struct {
+	Name        string
+	Description lang.MarkupContent
+	IsSensitive bool
+}
+
+// Type returns type of the module.NAME reference, i.e. an object
+// with attributes for each output, or a collection of such objects
+// if the module call has count or for_each. Types of output values
+// are not known statically and are therefore represented
+// as cty.DynamicPseudoType.
+func (mco *ModuleCallOutputs) Type() cty.Type {
+	attrTypes := make(map[string]cty.Type, len(mco.Outputs))
+	for name := range mco.Outputs {
+		attrTypes[name] = cty.DynamicPseudoType
+	}
+	objType := cty.Object(attrTypes)
+
+	switch {
+	case mco.HasCount:
+		return cty.List(objType)
+	case mco.HasForEach:
+		return cty.Map(objType)
+	}
+	return objType
+}
+
+// ModuleCallOutputs returns outputs of modules called from the parsed
+// files via local source, keyed by module call name.
+//
+// The module path must be set via SetModulePath for local sources
+// to be resolved. Calls of modules which cannot be read
+// (e.g. not yet created) are returned without outputs.
+func (m *SchemaMerger) ModuleCallOutputs() map[string]*ModuleCallOutputs {
+	calls, _ := refdecoder.DecodeModuleCalls(m.parsedFiles)
+
+	mcOutputs := make(map[string]*ModuleCallOutputs, 0)
+	for _, call := range calls {
+		if !module.IsLocalSource(call.Source) || m.modulePath == "" {
+			continue
+		}
+
+		mco := &ModuleCallOutputs{
+			Name:       call.Name,
+			Source:     call.Source,
+			HasCount:   call.HasCount,
+			HasForEach: call.HasForEach,
+			Outputs:    make(map[string]*ModuleOutput, 0),
+		}
+		mcOutputs[call.Name] = mco
+
+		files, err := module.ParseFiles(filepath.Join(m.modulePath, filepath.FromSlash(call.Source)))
+		if err != nil {
+			continue
+		}
+		outputs, _ := module.DecodeOutputs(files)
+		for name, o := range outputs {
+			mo := &ModuleOutput{
+				Name:        name,
+				IsSensitive: o.IsSensitive,
+			}
+			if o.Description != "" {
+				mo.Description = lang.PlainText(o.Description)
+			}
+			mco.Outputs[name] = mo
+		}
+	}
+
+	return mcOutputs
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaMerger_ModuleCallOutputs(t *testing.T) {
+	modulePath := filepath.Join("testdata", "workspace")
+	b, err := ioutil.ReadFile(filepath.Join(modulePath, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, diags := hclsyntax.ParseConfig(b, "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"main.tf": f,
+	})
+	sm.SetModulePath(modulePath)
+
+	expectedOutputs := map[string]*ModuleCallOutputs{
+		"network": {
+			Name:   "network",
+			Source: "./modules/network",
+			Outputs: map[string]*ModuleOutput{
+				"vpc_id": {
+					Name:        "vpc_id",
+					Description: lang.PlainText("ID of the VPC"),
+				},
+				"secret": {
+					Name:        "secret",
+					IsSensitive: true,
+				},
+			},
+		},
+		"storage": {
+			Name:     "storage",
+			Source:   "./modules/storage",
+			HasCount: true,
+			Outputs:  map[string]*ModuleOutput{},
+		},
+	}
+
+	outputs := sm.ModuleCallOutputs()
+	if diff := cmp.Diff(expectedOutputs, outputs); diff != "" {
+		t.Fatalf("module outputs mismatch: %s", diff)
+	}
+
+	expectedTypes := map[string]cty.Type{
+		"network": cty.Object(map[string]cty.Type{
+			"vpc_id": cty.DynamicPseudoType,
+			"secret": cty.DynamicPseudoType,
+		}),
+		"storage": cty.List(cty.EmptyObject),
+	}
+	for name, expectedType := range expectedTypes {
+		if !outputs[name].Type().Equals(expectedType) {
+			t.Fatalf("%s: type mismatch.\nexpected: %#v\ngiven: %#v",
+				name, expectedType, outputs[name].Type())
+		}
+	}
+}
+
+func TestSchemaMerger_ModuleCallOutputs_noModulePath(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(`module "network" {
+  source = "./modules/network"
+}
+`), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"main.tf": f,
+	})
+
+	outputs := sm.ModuleCallOutputs()
+	if len(outputs) != 0 {
+		t.Fatalf("expected no outputs without module path, given %#v", outputs)
+	}
+}
@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/internal/bodytype"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// typeForNestedAttributeType converts nested attribute type
+// (protocol 6) into an object type, or a collection of objects
+// depending on the nesting mode. Any attributes which are not required
+// are represented as optional object attributes.
+func typeForNestedAttributeType(nt *tfjson.SchemaNestedAttributeType) cty.Type {
+	objType := bodytype.ObjectType(&schema.BodySchema{
+		Attributes: convertAttributesFromJson(nt.Attributes),
+	})
+
+	switch nt.NestingMode {
+	case tfjson.SchemaNestingModeList:
+		return cty.List(objType)
+	case tfjson.SchemaNestingModeSet:
+		return cty.Set(objType)
+	case tfjson.SchemaNestingModeMap:
+		return cty.Map(objType)
+	}
+
+	return objType
+}
+
+func attributeType(attr *tfjson.SchemaAttribute) cty.Type {
+	if attr.AttributeNestedType != nil {
+		return typeForNestedAttributeType(attr.AttributeNestedType)
+	}
+	if attr.AttributeType == cty.NilType {
+		return cty.DynamicPseudoType
+	}
+	return attr.AttributeType
+}
+
+// nestedAttributeDescription returns description of an attribute
+// with nested attributes listed, as hcl-lang has no way
+// of describing individual attributes within an object
+func nestedAttributeDescription(attr *tfjson.SchemaAttribute) lang.MarkupContent {
+	var b strings.Builder
+
+	if attr.Description != "" {
+		b.WriteString(attr.Description)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Nested attributes:\n\n")
+	writeNestedAttributes(&b, attr.AttributeNestedType, 0)
+
+	return lang.Markdown(strings.TrimSuffix(b.String(), "\n"))
+}
+
+func writeNestedAttributes(b *strings.Builder, nt *tfjson.SchemaNestedAttributeType, depth int) {
+	names := make([]string, 0, len(nt.Attributes))
+	for name := range nt.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth)
+	for _, name := range names {
+		attr := nt.Attributes[name]
+
+		fmt.Fprintf(b, "%s- `%s` (%s)", indent, name, strings.Join(attributeFlags(attr), ", "))
+		if attr.Description != "" {
+			fmt.Fprintf(b, " - %s", attr.Description)
+		}
+		b.WriteString("\n")
+
+		if attr.AttributeNestedType != nil {
+			writeNestedAttributes(b, attr.AttributeNestedType, depth+1)
+		}
+	}
+}
+
+func attributeFlags(attr *tfjson.SchemaAttribute) []string {
+	flags := make([]string, 0)
+
+	switch {
+	case attr.Required:
+		flags = append(flags, "required")
+	case attr.Optional && attr.Computed:
+		flags = append(flags, "optional", "computed")
+	case attr.Optional:
+		flags = append(flags, "optional")
+	case attr.Computed:
+		flags = append(flags, "computed")
+	}
+	if attr.Sensitive {
+		flags = append(flags, "sensitive")
+	}
+	if attr.Deprecated {
+		flags = append(flags, "deprecated")
+	}
+
+	flags = append(flags, attributeType(attr).FriendlyNameForConstraint())
+
+	return flags
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/lang"
@@ -10,16 +11,34 @@ import (
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/hashicorp/terraform-schema/internal/addrs"
 	"github.com/hashicorp/terraform-schema/internal/refdecoder"
+	"github.com/hashicorp/terraform-schema/internal/schema/builtin"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type SchemaMerger struct {
 	coreSchema  *schema.BodySchema
 	parsedFiles map[string]*hcl.File
+	modulePath  string
 
 	coreVersion      *version.Version
 	providerVersions map[addrs.Provider]*version.Version
+	schemaLoader     ProviderSchemaLoader
+
+	// inheritedProviders maps local names to providers
+	// as passed from the parent module (if any)
+	inheritedProviders map[string]addrs.Provider
+}
+
+// ProviderSchemaLoader provides schemas of providers identified by
+// source address (e.g. registry.terraform.io/hashicorp/aws) and version,
+// such as providercache.Cache
+type ProviderSchemaLoader interface {
+	LoadProviderSchema(addr string, v *version.Version) (*tfjson.ProviderSchema, bool, error)
 }
 
+// NewSchemaMerger creates a new merger for the given core schema.
+// The core schema is never modified, so it is safe to share it
+// between multiple mergers, including concurrently running ones.
 func NewSchemaMerger(coreSchema *schema.BodySchema) *SchemaMerger {
 	return &SchemaMerger{
 		coreSchema:       coreSchema,
@@ -33,6 +52,13 @@ func (m *SchemaMerger) SetParsedFiles(files map[string]*hcl.File) {
 	m.parsedFiles = files
 }
 
+// SetModulePath sets path to the directory of the module
+// which the parsed files belong to, to enable resolution
+// of modules called via local source (e.g. ./modules/foo)
+func (m *SchemaMerger) SetModulePath(path string) {
+	m.modulePath = path
+}
+
 // SetCoreVersion sets version of Terraform (core) to help identify core schema
 // and schema of the builtin terraform provider
 func (m *SchemaMerger) SetCoreVersion(v *version.Version) {
@@ -57,19 +83,38 @@ func (m *SchemaMerger) SetProviderVersions(versions map[string]*version.Version)
 	return nil
 }
 
+// SetProviderSchemaLoader sets a loader which is consulted for schemas
+// of providers with versions known via SetProviderVersions
+// before falling back to terraform-json formatted schemas.
+//
+// Any errors returned by the loader (such as a corrupted cache entry)
+// are treated as if the schema was not found and reported as warnings
+// by MergeWithJsonProviderSchemas.
+func (m *SchemaMerger) SetProviderSchemaLoader(l ProviderSchemaLoader) {
+	m.schemaLoader = l
+}
+
 // MergeWithJsonProviderSchemas provides a merged schema based on
 // terraform-json formatted provider schema and any other data
-// provided via setters
-func (m *SchemaMerger) MergeWithJsonProviderSchemas(ps *tfjson.ProviderSchemas) (*schema.BodySchema, error) {
+// provided via setters.
+//
+// Provider references which cannot be decoded (such as legacy quoted
+// provider = "aws.west") are skipped and reported as warnings
+// alongside the merged schema.
+func (m *SchemaMerger) MergeWithJsonProviderSchemas(ps *tfjson.ProviderSchemas) (*schema.BodySchema, hcl.Diagnostics, error) {
 	if m.coreSchema == nil {
-		return nil, coreSchemaRequiredErr{}
+		return nil, nil, coreSchemaRequiredErr{}
 	}
 
-	if ps == nil {
-		return m.coreSchema, nil
+	mergedSchema := copyBodySchema(m.coreSchema)
+
+	if m.modulePath != "" {
+		m.mergeModuleInputs(mergedSchema)
 	}
 
-	mergedSchema := m.coreSchema
+	if ps == nil && m.coreVersion == nil && m.schemaLoader == nil {
+		return mergedSchema, nil, nil
+	}
 
 	if mergedSchema.Blocks["provider"].DependentBody == nil {
 		mergedSchema.Blocks["provider"].DependentBody = make(map[schema.SchemaKey]*schema.BodySchema)
@@ -81,24 +126,31 @@ func (m *SchemaMerger) MergeWithJsonProviderSchemas(ps *tfjson.ProviderSchemas)
 		mergedSchema.Blocks["data"].DependentBody = make(map[schema.SchemaKey]*schema.BodySchema)
 	}
 
-	refs, err := refdecoder.DecodeProviderReferences(m.parsedFiles)
-	if err != nil {
-		return m.coreSchema, err
+	refs, refDiags := refdecoder.DecodeProviderReferencesWithInherited(m.parsedFiles, m.inheritedProviders)
+	diags := asWarnings(refDiags)
+
+	if m.coreVersion != nil {
+		m.mergeBuiltinTerraformProvider(mergedSchema, refs)
 	}
 
-	for sourceString, provider := range ps.Schemas {
-		srcAddr, err := addrs.ParseProviderSourceString(sourceString)
-		if err != nil {
-			return m.coreSchema, err
-		}
+	schemas, loaderDiags, err := m.providerSchemas(ps)
+	diags = append(diags, loaderDiags...)
+	if err != nil {
+		return mergedSchema, diags, err
+	}
 
+	for srcAddr, provider := range schemas {
 		localRefs := refs.LocalNamesByAddr(srcAddr)
 
 		if len(localRefs) == 0 && (srcAddr.IsBuiltIn() || srcAddr.IsLegacy() || srcAddr.IsDefault()) {
 			// Assume this provider does not have alias
-			localRefs = append(localRefs, addrs.LocalProviderConfig{
+			// unless the local name is already used by another provider
+			localRef := addrs.LocalProviderConfig{
 				LocalName: srcAddr.Type,
-			})
+			}
+			if _, taken := refs[localRef]; !taken {
+				localRefs = append(localRefs, localRef)
+			}
 		}
 
 		var providerSchema *tfjson.SchemaBlock
@@ -117,53 +169,184 @@ func (m *SchemaMerger) MergeWithJsonProviderSchemas(ps *tfjson.ProviderSchemas)
 
 			for rName, rJsonSchema := range provider.ResourceSchemas {
 				rSchema := convertBodySchemaFromJson(detail, rJsonSchema.Block)
-
-				depKeys := schema.DependencyKeys{
-					Labels: []schema.LabelDependent{
-						{Index: 0, Value: rName},
-					},
-				}
-				if localRef.Alias != "" {
-					depKeys.Attributes = append(depKeys.Attributes, schema.AttributeDependent{
-						Name: "provider",
-						Expr: schema.ExpressionValue{
-							Reference: lang.Reference{
-								lang.RootStep{Name: localRef.LocalName},
-								lang.AttrStep{Name: localRef.Alias},
-							},
-						},
-					})
-				}
+				depKeys := dependencyKeysForLocalRef(rName, localRef)
 
 				mergedSchema.Blocks["resource"].DependentBody[schema.NewSchemaKey(depKeys)] = rSchema
 			}
 
 			for dsName, dsJsonSchema := range provider.DataSourceSchemas {
 				dsSchema := convertBodySchemaFromJson(detail, dsJsonSchema.Block)
-
-				depKeys := schema.DependencyKeys{
-					Labels: []schema.LabelDependent{
-						{Index: 0, Value: dsName},
-					},
-				}
-				if localRef.Alias != "" {
-					depKeys.Attributes = append(depKeys.Attributes, schema.AttributeDependent{
-						Name: "provider",
-						Expr: schema.ExpressionValue{
-							Reference: lang.Reference{
-								lang.RootStep{Name: localRef.LocalName},
-								lang.AttrStep{Name: localRef.Alias},
-							},
-						},
-					})
-				}
+				depKeys := dependencyKeysForLocalRef(dsName, localRef)
 
 				mergedSchema.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = dsSchema
 			}
 		}
 	}
 
-	return mergedSchema, nil
+	return mergedSchema, diags, nil
+}
+
+// asWarnings returns copies of the given diagnostics as warnings,
+// for problems which only cause part of the configuration to be skipped
+func asWarnings(diags hcl.Diagnostics) hcl.Diagnostics {
+	if len(diags) == 0 {
+		return nil
+	}
+
+	warnings := make(hcl.Diagnostics, len(diags))
+	for i, diag := range diags {
+		warning := *diag
+		warning.Severity = hcl.DiagWarning
+		warnings[i] = &warning
+	}
+	return warnings
+}
+
+// providerSchemas returns schemas for providers of known versions
+// from the loader (if any), and for any other providers
+// from the given terraform-json formatted schemas
+func (m *SchemaMerger) providerSchemas(ps *tfjson.ProviderSchemas) (map[addrs.Provider]*tfjson.ProviderSchema, hcl.Diagnostics, error) {
+	var diags hcl.Diagnostics
+	schemas := make(map[addrs.Provider]*tfjson.ProviderSchema, 0)
+
+	if ps != nil {
+		for sourceString, provider := range ps.Schemas {
+			srcAddr, err := addrs.ParseProviderSourceString(sourceString)
+			if err != nil {
+				return nil, diags, err
+			}
+			schemas[srcAddr] = provider
+		}
+	}
+
+	if m.schemaLoader == nil {
+		return schemas, diags, nil
+	}
+
+	// Load schemas in a stable order to produce stable diagnostics
+	srcAddrs := make([]addrs.Provider, 0, len(m.providerVersions))
+	for srcAddr := range m.providerVersions {
+		srcAddrs = append(srcAddrs, srcAddr)
+	}
+	sort.Slice(srcAddrs, func(i, j int) bool {
+		return srcAddrs[i].String() < srcAddrs[j].String()
+	})
+
+	for _, srcAddr := range srcAddrs {
+		ver := m.providerVersions[srcAddr]
+		provider, ok, err := m.schemaLoader.LoadProviderSchema(srcAddr.String(), ver)
+		if err != nil {
+			// Treat any loader errors (such as a corrupted cache entry)
+			// as a miss, so that schemas of other providers, or the given
+			// schema of the same provider (if any) can still be merged
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Failed to load provider schema",
+				Detail: fmt.Sprintf("Schema of %s %s could not be loaded: %s.",
+					srcAddr.ForDisplay(), ver.String(), err),
+			})
+			continue
+		}
+		if ok {
+			schemas[srcAddr] = provider
+		}
+	}
+
+	return schemas, diags, nil
+}
+
+// mergeBuiltinTerraformProvider merges schema of the built-in terraform
+// provider bundled with this library for the core version
+func (m *SchemaMerger) mergeBuiltinTerraformProvider(mergedSchema *schema.BodySchema, refs addrs.ProviderReferences) {
+	srcAddr := addrs.NewBuiltInProvider("terraform")
+	ps := builtin.TerraformProvider(m.coreVersion)
+	detail := m.detailForSrcAddr(srcAddr)
+
+	localRefs := refs.LocalNamesByAddr(srcAddr)
+	if len(localRefs) == 0 {
+		localRefs = append(localRefs, addrs.LocalProviderConfig{
+			LocalName: srcAddr.Type,
+		})
+	}
+
+	for _, localRef := range localRefs {
+		mergedSchema.Blocks["provider"].DependentBody[schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: localRef.LocalName},
+			},
+		})] = withDetail(ps.Provider, detail)
+
+		for rName, rSchema := range ps.Resources {
+			depKeys := dependencyKeysForLocalRef(rName, localRef)
+			mergedSchema.Blocks["resource"].DependentBody[schema.NewSchemaKey(depKeys)] = withDetail(rSchema, detail)
+		}
+
+		for dsName, dsSchema := range ps.DataSources {
+			depKeys := dependencyKeysForLocalRef(dsName, localRef)
+			mergedSchema.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = withDetail(dsSchema, detail)
+		}
+
+		// backend is declared as a dependency key in the body
+		// of terraform_remote_state only (rather than in the body
+		// shared by all data sources), so the backend-specific bodies
+		// are resolved through the body keyed on the type label
+		for backendType, dsSchema := range ps.RemoteStateByBackend {
+			depKeys := dependencyKeysForLocalRef("terraform_remote_state", localRef)
+			depKeys.Attributes = append(depKeys.Attributes, schema.AttributeDependent{
+				Name: "backend",
+				Expr: schema.ExpressionValue{
+					Static: cty.StringVal(backendType),
+				},
+			})
+			mergedSchema.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = withDetail(dsSchema, detail)
+		}
+	}
+}
+
+func withDetail(bs *schema.BodySchema, detail string) *schema.BodySchema {
+	newBs := copyBodySchema(bs)
+	newBs.Detail = detail
+	return newBs
+}
+
+// dependencyKeysForLocalRef returns keys for the body of a resource
+// or data source of the given type, provided by the given provider config.
+//
+// Bodies are keyed on the type alone if the resource would use the config
+// implicitly (without the provider meta-argument), i.e. when the config
+// is not aliased and its local name is the one implied by the type.
+func dependencyKeysForLocalRef(typeName string, localRef addrs.LocalProviderConfig) schema.DependencyKeys {
+	depKeys := schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: typeName},
+		},
+	}
+
+	if localRef.Alias != "" {
+		depKeys.Attributes = append(depKeys.Attributes, schema.AttributeDependent{
+			Name: "provider",
+			Expr: schema.ExpressionValue{
+				Reference: lang.Reference{
+					lang.RootStep{Name: localRef.LocalName},
+					lang.AttrStep{Name: localRef.Alias},
+				},
+			},
+		})
+		return depKeys
+	}
+
+	if localRef.LocalName != addrs.ImpliedProviderLocalName(typeName) {
+		depKeys.Attributes = append(depKeys.Attributes, schema.AttributeDependent{
+			Name: "provider",
+			Expr: schema.ExpressionValue{
+				Reference: lang.Reference{
+					lang.RootStep{Name: localRef.LocalName},
+				},
+			},
+		})
+	}
+
+	return depKeys
 }
 
 func (m *SchemaMerger) detailForSrcAddr(addr addrs.Provider) string {
@@ -208,8 +391,18 @@ func convertBlocksFromJson(blocks map[string]*tfjson.SchemaBlockType) map[string
 
 		blockType := schema.BlockTypeNil
 		labels := []*schema.LabelSchema{}
+		minItems, maxItems := jsonSchema.MinItems, jsonSchema.MaxItems
 
 		switch jsonSchema.NestingMode {
+		case tfjson.SchemaNestingModeSingle:
+			blockType = schema.BlockTypeObject
+			maxItems = 1
+		case tfjson.SchemaNestingModeGroup:
+			// group blocks are always present in the resulting value
+			// (with nested attributes null) even if not declared,
+			// so they are never required, but cannot be repeated
+			blockType = schema.BlockTypeObject
+			minItems, maxItems = 0, 1
 		case tfjson.SchemaNestingModeMap:
 			labels = []*schema.LabelSchema{
 				{Name: "name"},
@@ -225,8 +418,8 @@ func convertBlocksFromJson(blocks map[string]*tfjson.SchemaBlockType) map[string
 			Description:  markupContent(block.Description, block.DescriptionKind),
 			Type:         blockType,
 			IsDeprecated: block.Deprecated,
-			MinItems:     jsonSchema.MinItems,
-			MaxItems:     jsonSchema.MaxItems,
+			MinItems:     minItems,
+			MaxItems:     maxItems,
 			Labels:       labels,
 			Body:         convertBodySchemaFromJson("", block),
 		}
@@ -237,7 +430,7 @@ func convertBlocksFromJson(blocks map[string]*tfjson.SchemaBlockType) map[string
 func convertAttributesFromJson(attributes map[string]*tfjson.SchemaAttribute) map[string]*schema.AttributeSchema {
 	cAttrs := make(map[string]*schema.AttributeSchema, len(attributes))
 	for name, attr := range attributes {
-		cAttrs[name] = &schema.AttributeSchema{
+		cAttr := &schema.AttributeSchema{
 			Description:  markupContent(attr.Description, attr.DescriptionKind),
 			IsDeprecated: attr.Deprecated,
 			IsComputed:   attr.Computed,
@@ -245,6 +438,11 @@ func convertAttributesFromJson(attributes map[string]*tfjson.SchemaAttribute) ma
 			IsRequired:   attr.Required,
 			ValueType:    attr.AttributeType,
 		}
+		if attr.AttributeNestedType != nil {
+			cAttr.ValueType = typeForNestedAttributeType(attr.AttributeNestedType)
+			cAttr.Description = nestedAttributeDescription(attr)
+		}
+		cAttrs[name] = cAttr
 	}
 	return cAttrs
 }
@@ -4,21 +4,26 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/providercache"
 	"github.com/zclconf/go-cty/cty"
 )
 
 func TestMergeWithJsonProviderSchemas_noCoreSchema(t *testing.T) {
 	sm := NewSchemaMerger(nil)
 
-	_, err := sm.MergeWithJsonProviderSchemas(nil)
+	_, _, err := sm.MergeWithJsonProviderSchemas(nil)
 	if err == nil {
 		t.Fatal("expected error for nil core schema")
 	}
@@ -31,7 +36,7 @@ func TestMergeWithJsonProviderSchemas_noCoreSchema(t *testing.T) {
 func TestMergeWithJsonProviderSchemas_noProviderSchema(t *testing.T) {
 	sm := NewSchemaMerger(testCoreSchema)
 
-	_, err := sm.MergeWithJsonProviderSchemas(nil)
+	_, _, err := sm.MergeWithJsonProviderSchemas(nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -62,7 +67,7 @@ func TestMergeWithJsonProviderSchemas_v012(t *testing.T) {
 		"test.tf": f,
 	})
 
-	mergedSchema, err := sm.MergeWithJsonProviderSchemas(ps)
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(ps)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -101,7 +106,7 @@ func TestMergeWithJsonProviderSchemas_v013(t *testing.T) {
 		"test.tf": f,
 	})
 
-	mergedSchema, err := sm.MergeWithJsonProviderSchemas(ps)
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(ps)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -115,6 +120,728 @@ func TestMergeWithJsonProviderSchemas_v013(t *testing.T) {
 	}
 }
 
+func TestMergeWithJsonProviderSchemas_coreSchemaUnchanged(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/test-config-0.13.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, diags := hclsyntax.ParseConfig(b, "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	ps := &tfjson.ProviderSchemas{}
+	b, err = ioutil.ReadFile("testdata/provider-schemas-0.13.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(b, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coreSchema := copyBodySchema(testCoreSchema)
+
+	var wg sync.WaitGroup
+	mergedSchemas := make([]*schema.BodySchema, 4)
+	for i := range mergedSchemas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sm := NewSchemaMerger(coreSchema)
+			sm.SetParsedFiles(map[string]*hcl.File{
+				"test.tf": f,
+			})
+			mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(ps)
+			if err != nil {
+				t.Error(err)
+			}
+			mergedSchemas[i] = mergedSchema
+		}(i)
+	}
+	wg.Wait()
+
+	opts := cmp.Options{
+		cmpopts.IgnoreUnexported(cty.Type{}),
+	}
+
+	if diff := cmp.Diff(testCoreSchema, coreSchema, opts); diff != "" {
+		t.Fatalf("core schema was modified: %s", diff)
+	}
+
+	if mergedSchemas[0] == mergedSchemas[1] {
+		t.Fatal("expected independent merged schemas")
+	}
+	mergedSchemas[0].Blocks["resource"].DependentBody = nil
+	if diff := cmp.Diff(expectedMergedSchema_v013, mergedSchemas[1], opts); diff != "" {
+		t.Fatalf("schema differs: %s", diff)
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_builtinTerraformProvider(t *testing.T) {
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetCoreVersion(version.Must(version.NewVersion("0.14.0")))
+
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataBlock := mergedSchema.Blocks["data"]
+	rsSchema, ok := dataBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "terraform_remote_state"},
+		},
+	})
+	if !ok {
+		t.Fatal("expected terraform_remote_state data source")
+	}
+	if rsSchema.Detail != "(builtin 0.14.0)" {
+		t.Fatalf("unexpected detail: %q", rsSchema.Detail)
+	}
+	if !rsSchema.Attributes["backend"].IsDepKey {
+		t.Fatal("expected backend attribute to be a dependency key")
+	}
+
+	if _, ok := dataBlock.Body.Attributes["backend"]; ok {
+		t.Fatal("expected backend attribute not to be declared for all data sources")
+	}
+
+	s3Schema, ok := dataBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "terraform_remote_state"},
+		},
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "backend",
+				Expr: schema.ExpressionValue{
+					Static: cty.StringVal("s3"),
+				},
+			},
+		},
+	})
+	if !ok {
+		t.Fatal("expected terraform_remote_state data source for s3 backend")
+	}
+	configType := s3Schema.Attributes["config"].ValueType
+	if !configType.IsObjectType() || !configType.HasAttribute("bucket") {
+		t.Fatalf("expected s3 config object type, given: %s", configType.FriendlyName())
+	}
+	if !configType.AttributeOptional("bucket") {
+		t.Fatal("expected bucket to be optional in s3 config")
+	}
+
+	_, ok = mergedSchema.Blocks["resource"].DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "terraform_data"},
+		},
+	})
+	if ok {
+		t.Fatal("terraform_data resource not expected in 0.14.0")
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_builtinTerraformProviderNoVersion(t *testing.T) {
+	sm := NewSchemaMerger(testCoreSchema)
+
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mergedSchema.Blocks["data"].DependentBody) > 0 {
+		t.Fatal("expected no dependent bodies without core version")
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_impliedProvider(t *testing.T) {
+	cfg := `
+terraform {
+  required_providers {
+    google-beta = {
+      source = "hashicorp/google-beta"
+    }
+  }
+}
+
+resource "google_compute_instance" "ga" {
+}
+
+resource "google_compute_instance" "beta" {
+  provider = google-beta
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	resourceSchema := func(attrName string) *tfjson.Schema {
+		return &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					attrName: {AttributeType: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/google": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_compute_instance": resourceSchema("ga_attr"),
+				},
+			},
+			"registry.terraform.io/hashicorp/google-beta": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_compute_instance": resourceSchema("beta_attr"),
+				},
+			},
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"test.tf": f,
+	})
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceBlock := mergedSchema.Blocks["resource"]
+
+	gaSchema, ok := resourceBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "google_compute_instance"},
+		},
+	})
+	if !ok {
+		t.Fatal("expected schema for implied google provider")
+	}
+	if _, ok := gaSchema.Attributes["ga_attr"]; !ok {
+		t.Fatalf("expected google provider schema, given %q", gaSchema.Detail)
+	}
+
+	betaSchema, ok := resourceBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "google_compute_instance"},
+		},
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "provider",
+				Expr: schema.ExpressionValue{
+					Reference: lang.Reference{
+						lang.RootStep{Name: "google-beta"},
+					},
+				},
+			},
+		},
+	})
+	if !ok {
+		t.Fatal("expected schema for explicit google-beta provider")
+	}
+	if _, ok := betaSchema.Attributes["beta_attr"]; !ok {
+		t.Fatalf("expected google-beta provider schema, given %q", betaSchema.Detail)
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_invalidProviderReference(t *testing.T) {
+	cfg := `
+provider "aws" {
+  alias = "west"
+}
+
+resource "aws_instance" "legacy" {
+  provider = "aws.west"
+}
+
+resource "google_compute_instance" "valid" {
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	resourceSchema := func(attrName string) *tfjson.Schema {
+		return &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					attrName: {AttributeType: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_instance": resourceSchema("ami"),
+				},
+			},
+			"registry.terraform.io/hashicorp/google": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_compute_instance": resourceSchema("machine_type"),
+				},
+			},
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"test.tf": f,
+	})
+	mergedSchema, diags, err := sm.MergeWithJsonProviderSchemas(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly 1 diagnostic, given: %#v", diags)
+	}
+	if diags[0].Severity != hcl.DiagWarning {
+		t.Fatalf("expected warning, given: %#v", diags[0])
+	}
+	expectedRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 7, Column: 14, Byte: 86},
+		End:      hcl.Pos{Line: 7, Column: 24, Byte: 96},
+	}
+	if diff := cmp.Diff(expectedRange, *diags[0].Subject); diff != "" {
+		t.Fatalf("diagnostic range mismatch: %s", diff)
+	}
+
+	resourceBlock := mergedSchema.Blocks["resource"]
+	for rType, attrName := range map[string]string{
+		"aws_instance":            "ami",
+		"google_compute_instance": "machine_type",
+	} {
+		bodySchema, ok := resourceBlock.DependentBodySchema(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: rType},
+			},
+		})
+		if !ok {
+			t.Fatalf("expected schema for %s", rType)
+		}
+		if _, ok := bodySchema.Attributes[attrName]; !ok {
+			t.Fatalf("expected %s attribute in %s schema", attrName, rType)
+		}
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_schemaLoader(t *testing.T) {
+	cfg := `
+provider "aws" {
+}
+
+provider "google" {
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	providerSchema := func(attrName string) *tfjson.ProviderSchema {
+		return &tfjson.ProviderSchema{
+			ConfigSchema: &tfjson.Schema{
+				Block: &tfjson.SchemaBlock{
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						attrName: {AttributeType: cty.String, Optional: true},
+					},
+				},
+			},
+		}
+	}
+
+	loader := testSchemaLoader{
+		"registry.terraform.io/hashicorp/aws@3.12.0": providerSchema("cached_attr"),
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws":    providerSchema("json_attr"),
+			"registry.terraform.io/hashicorp/google": providerSchema("json_attr"),
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"test.tf": f,
+	})
+	err := sm.SetProviderVersions(map[string]*version.Version{
+		"hashicorp/aws":    version.Must(version.NewVersion("3.12.0")),
+		"hashicorp/google": version.Must(version.NewVersion("3.50.0")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.SetProviderSchemaLoader(loader)
+
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedAttrs := map[string]string{
+		"aws":    "cached_attr",
+		"google": "json_attr",
+	}
+	for name, attrName := range expectedAttrs {
+		bs, ok := mergedSchema.Blocks["provider"].DependentBodySchema(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: name},
+			},
+		})
+		if !ok {
+			t.Fatalf("expected schema for %s provider", name)
+		}
+		if _, ok := bs.Attributes[attrName]; !ok {
+			t.Fatalf("expected %q attribute in %s provider schema", attrName, name)
+		}
+	}
+}
+
+func TestMergeWithJsonProviderSchemas_schemaLoaderError(t *testing.T) {
+	cfg := `
+provider "aws" {
+}
+
+provider "google" {
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	loader := failingSchemaLoader{
+		"registry.terraform.io/hashicorp/aws": &providercache.CorruptedEntryError{
+			Path: "aws.json",
+			Err:  errors.New("unexpected end of JSON input"),
+		},
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/google": {
+				ConfigSchema: &tfjson.Schema{
+					Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"project": {AttributeType: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"test.tf": f,
+	})
+	err := sm.SetProviderVersions(map[string]*version.Version{
+		"hashicorp/aws":    version.Must(version.NewVersion("3.12.0")),
+		"hashicorp/google": version.Must(version.NewVersion("3.50.0")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.SetProviderSchemaLoader(loader)
+
+	mergedSchema, diags, err := sm.MergeWithJsonProviderSchemas(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "Failed to load provider schema",
+			Detail: "Schema of hashicorp/aws 3.12.0 could not be loaded: " +
+				"corrupted cache entry aws.json: unexpected end of JSON input.",
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+
+	providerBlock := mergedSchema.Blocks["provider"]
+	_, ok := providerBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "aws"},
+		},
+	})
+	if ok {
+		t.Fatal("expected no schema for aws provider")
+	}
+	bs, ok := providerBlock.DependentBodySchema(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "google"},
+		},
+	})
+	if !ok {
+		t.Fatal("expected schema for google provider")
+	}
+	if _, ok := bs.Attributes["project"]; !ok {
+		t.Fatal("expected \"project\" attribute in google provider schema")
+	}
+}
+
+type testSchemaLoader map[string]*tfjson.ProviderSchema
+
+func (l testSchemaLoader) LoadProviderSchema(addr string, v *version.Version) (*tfjson.ProviderSchema, bool, error) {
+	ps, ok := l[addr+"@"+v.String()]
+	return ps, ok, nil
+}
+
+type failingSchemaLoader map[string]error
+
+func (l failingSchemaLoader) LoadProviderSchema(addr string, v *version.Version) (*tfjson.ProviderSchema, bool, error) {
+	if err, ok := l[addr]; ok {
+		return nil, false, err
+	}
+	return nil, false, nil
+}
+
+func TestMergeWithJsonProviderSchemas_moduleInputs(t *testing.T) {
+	modulePath := filepath.Join("testdata", "workspace")
+	b, err := ioutil.ReadFile(filepath.Join(modulePath, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, diags := hclsyntax.ParseConfig(b, "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	coreSchema, err := CoreModuleSchemaForVersion(version.Must(version.NewVersion("0.15.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm := NewSchemaMerger(coreSchema)
+	sm.SetParsedFiles(map[string]*hcl.File{
+		"main.tf": f,
+	})
+	sm.SetModulePath(modulePath)
+
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moduleBlock := mergedSchema.Blocks["module"]
+	if len(moduleBlock.DependentBody) != 2 {
+		t.Fatalf("expected 2 dependent bodies (for local modules), given %d",
+			len(moduleBlock.DependentBody))
+	}
+
+	inputsSchema, ok := moduleBlock.DependentBodySchema(schema.DependencyKeys{
+		Attributes: []schema.AttributeDependent{
+			{
+				Name: "source",
+				Expr: schema.ExpressionValue{
+					Static: cty.StringVal("./modules/network"),
+				},
+			},
+		},
+	})
+	if !ok {
+		t.Fatal("expected schema for ./modules/network")
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"cidr_block": {
+				Description: lang.Markdown("CIDR block of the VPC"),
+				IsRequired:  true,
+				ValueType:   cty.String,
+			},
+			"tags": {
+				Description: lang.Markdown("Default: `{}`"),
+				IsOptional:  true,
+				ValueType:   cty.Map(cty.String),
+			},
+			"password": {
+				Description: lang.Markdown("Default: (sensitive)"),
+				IsOptional:  true,
+				ValueType:   cty.DynamicPseudoType,
+			},
+		},
+		Detail: "./modules/network",
+	}
+	if diff := cmp.Diff(expectedSchema, inputsSchema, cmp.Comparer(cty.Type.Equals)); diff != "" {
+		t.Fatalf("module inputs schema mismatch: %s", diff)
+	}
+
+	// core schema of the module block is unchanged
+	if _, ok := coreSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(moduleSourceDependencyKeys("./modules/network"))]; ok {
+		t.Fatal("core schema was modified")
+	}
+}
+
+func TestConvertAttributesFromJson_nestedType(t *testing.T) {
+	attrs := map[string]*tfjson.SchemaAttribute{
+		"single": {
+			AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+				NestingMode: tfjson.SchemaNestingModeSingle,
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"name":   {AttributeType: cty.String, Required: true, Description: "Name of the thing"},
+					"secret": {AttributeType: cty.String, Optional: true, Sensitive: true},
+				},
+			},
+			Optional:        true,
+			Description:     "Single settings",
+			DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+		},
+		"list": {
+			AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+				NestingMode: tfjson.SchemaNestingModeList,
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"id": {AttributeType: cty.String, Computed: true},
+					"rules": {
+						AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+							NestingMode: tfjson.SchemaNestingModeSet,
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"port": {AttributeType: cty.Number, Optional: true, Computed: true},
+							},
+						},
+						Required: true,
+					},
+				},
+				MinItems: 1,
+			},
+			Required: true,
+		},
+		"map": {
+			AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+				NestingMode: tfjson.SchemaNestingModeMap,
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					"value": {AttributeType: cty.String, Required: true},
+				},
+			},
+			Computed: true,
+		},
+	}
+
+	expectedAttrs := map[string]*schema.AttributeSchema{
+		"single": {
+			IsOptional: true,
+			ValueType: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name":   cty.String,
+				"secret": cty.String,
+			}, []string{"secret"}),
+			Description: lang.Markdown("Single settings\n\nNested attributes:\n\n" +
+				"- `name` (required, string) - Name of the thing\n" +
+				"- `secret` (optional, sensitive, string)"),
+		},
+		"list": {
+			IsRequired: true,
+			ValueType: cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"id": cty.String,
+				"rules": cty.Set(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+					"port": cty.Number,
+				}, []string{"port"})),
+			}, []string{"id"})),
+			Description: lang.Markdown("Nested attributes:\n\n" +
+				"- `id` (computed, string)\n" +
+				"- `rules` (required, set of object)\n" +
+				"  - `port` (optional, computed, number)"),
+		},
+		"map": {
+			IsComputed: true,
+			ValueType: cty.Map(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"value": cty.String,
+			}, []string{})),
+			Description: lang.Markdown("Nested attributes:\n\n" +
+				"- `value` (required, string)"),
+		},
+	}
+
+	givenAttrs := convertAttributesFromJson(attrs)
+	if diff := cmp.Diff(expectedAttrs, givenAttrs, cmp.Comparer(cty.Type.Equals)); diff != "" {
+		t.Fatalf("attributes mismatch: %s", diff)
+	}
+}
+
+func TestConvertBlocksFromJson_nestingModes(t *testing.T) {
+	body := &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"create": {AttributeType: cty.String, Optional: true},
+		},
+	}
+	blocks := map[string]*tfjson.SchemaBlockType{
+		"single": {
+			NestingMode: tfjson.SchemaNestingModeSingle,
+			Block:       body,
+		},
+		"group": {
+			NestingMode: tfjson.SchemaNestingModeGroup,
+			Block:       body,
+		},
+		"list": {
+			NestingMode: tfjson.SchemaNestingModeList,
+			Block:       body,
+			MinItems:    1,
+			MaxItems:    3,
+		},
+		"set": {
+			NestingMode: tfjson.SchemaNestingModeSet,
+			Block:       body,
+		},
+		"map": {
+			NestingMode: tfjson.SchemaNestingModeMap,
+			Block:       body,
+		},
+	}
+
+	expectedBody := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"create": {ValueType: cty.String, IsOptional: true},
+		},
+		Blocks: map[string]*schema.BlockSchema{},
+	}
+	expectedBlocks := map[string]*schema.BlockSchema{
+		"single": {
+			Type:     schema.BlockTypeObject,
+			MaxItems: 1,
+			Labels:   []*schema.LabelSchema{},
+			Body:     expectedBody,
+		},
+		"group": {
+			Type:     schema.BlockTypeObject,
+			MaxItems: 1,
+			Labels:   []*schema.LabelSchema{},
+			Body:     expectedBody,
+		},
+		"list": {
+			Type:     schema.BlockTypeList,
+			MinItems: 1,
+			MaxItems: 3,
+			Labels:   []*schema.LabelSchema{},
+			Body:     expectedBody,
+		},
+		"set": {
+			Type:   schema.BlockTypeSet,
+			Labels: []*schema.LabelSchema{},
+			Body:   expectedBody,
+		},
+		"map": {
+			Type: schema.BlockTypeMap,
+			Labels: []*schema.LabelSchema{
+				{Name: "name"},
+			},
+			Body: expectedBody,
+		},
+	}
+
+	givenBlocks := convertBlocksFromJson(blocks)
+	if diff := cmp.Diff(expectedBlocks, givenBlocks, cmp.Comparer(cty.Type.Equals)); diff != "" {
+		t.Fatalf("blocks mismatch: %s", diff)
+	}
+}
+
 var testCoreSchema = &schema.BodySchema{
 	Blocks: map[string]*schema.BlockSchema{
 		"provider": {
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SensitiveAttributePaths returns paths of attributes marked as sensitive
+// by providers, keyed by resource type.
+//
+// hcl-lang's AttributeSchema has no way of expressing sensitivity,
+// so this is provided alongside the merged schema. Paths include attributes
+// within nested blocks and nested attribute types. Nested blocks and
+// attributes of any nesting mode are represented by a cty.GetAttrStep,
+// i.e. without steps to address individual elements of a collection.
+//
+// If the same resource type is provided by more than one provider
+// (e.g. google and google-beta) the paths are combined.
+func SensitiveAttributePaths(ps *tfjson.ProviderSchemas) map[string][]cty.Path {
+	paths := make(map[string][]cty.Path, 0)
+	if ps == nil {
+		return paths
+	}
+
+	for _, pSchema := range ps.Schemas {
+		for rName, rSchema := range pSchema.ResourceSchemas {
+			if rSchema == nil {
+				continue
+			}
+			rPaths := sensitivePathsForBlock(cty.Path{}, rSchema.Block)
+			if len(rPaths) == 0 {
+				continue
+			}
+			paths[rName] = appendUniquePaths(paths[rName], rPaths...)
+		}
+	}
+
+	for rName := range paths {
+		sortPaths(paths[rName])
+	}
+
+	return paths
+}
+
+func sensitivePathsForBlock(prefix cty.Path, block *tfjson.SchemaBlock) []cty.Path {
+	if block == nil {
+		return nil
+	}
+
+	paths := sensitivePathsForAttributes(prefix, block.Attributes)
+	for name, nb := range block.NestedBlocks {
+		paths = append(paths, sensitivePathsForBlock(extendPath(prefix, name), nb.Block)...)
+	}
+
+	return paths
+}
+
+func sensitivePathsForAttributes(prefix cty.Path, attributes map[string]*tfjson.SchemaAttribute) []cty.Path {
+	paths := make([]cty.Path, 0)
+	for name, attr := range attributes {
+		path := extendPath(prefix, name)
+		if attr.Sensitive {
+			paths = append(paths, path)
+		}
+		if attr.AttributeNestedType != nil {
+			paths = append(paths, sensitivePathsForAttributes(path,
+				attr.AttributeNestedType.Attributes)...)
+		}
+	}
+	return paths
+}
+
+// extendPath returns a new path, to avoid sharing
+// the underlying array between sibling paths
+func extendPath(prefix cty.Path, name string) cty.Path {
+	path := make(cty.Path, len(prefix), len(prefix)+1)
+	copy(path, prefix)
+	return path.GetAttr(name)
+}
+
+func appendUniquePaths(paths []cty.Path, newPaths ...cty.Path) []cty.Path {
+	for _, newPath := range newPaths {
+		exists := false
+		for _, path := range paths {
+			if path.Equals(newPath) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			paths = append(paths, newPath)
+		}
+	}
+	return paths
+}
+
+func sortPaths(paths []cty.Path) {
+	sort.Slice(paths, func(i, j int) bool {
+		return pathString(paths[i]) < pathString(paths[j])
+	})
+}
+
+func pathString(path cty.Path) string {
+	s := ""
+	for i, step := range path {
+		if ga, ok := step.(cty.GetAttrStep); ok {
+			if i > 0 {
+				s += "."
+			}
+			s += ga.Name
+		}
+	}
+	return s
+}
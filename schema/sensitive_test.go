@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSensitiveAttributePaths(t *testing.T) {
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/google": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_sql_user": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"name":     {AttributeType: cty.String, Required: true},
+								"password": {AttributeType: cty.String, Optional: true, Sensitive: true},
+							},
+						},
+					},
+					"google_compute_instance": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"name": {AttributeType: cty.String, Required: true},
+							},
+						},
+					},
+				},
+			},
+			"registry.terraform.io/hashicorp/google-beta": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_sql_user": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"password": {AttributeType: cty.String, Optional: true, Sensitive: true},
+								"token":    {AttributeType: cty.String, Computed: true, Sensitive: true},
+							},
+						},
+					},
+				},
+			},
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_db_instance": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"credentials": {
+									AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+										NestingMode: tfjson.SchemaNestingModeSingle,
+										Attributes: map[string]*tfjson.SchemaAttribute{
+											"username": {AttributeType: cty.String, Optional: true},
+											"password": {AttributeType: cty.String, Optional: true, Sensitive: true},
+										},
+									},
+									Optional: true,
+								},
+							},
+							NestedBlocks: map[string]*tfjson.SchemaBlockType{
+								"s3_import": {
+									NestingMode: tfjson.SchemaNestingModeList,
+									Block: &tfjson.SchemaBlock{
+										Attributes: map[string]*tfjson.SchemaAttribute{
+											"bucket_name": {AttributeType: cty.String, Required: true},
+											"secret_key":  {AttributeType: cty.String, Optional: true, Sensitive: true},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				DataSourceSchemas: map[string]*tfjson.Schema{
+					"aws_secret": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"value": {AttributeType: cty.String, Computed: true, Sensitive: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expectedPaths := map[string][]cty.Path{
+		"aws_db_instance": {
+			cty.GetAttrPath("credentials").GetAttr("password"),
+			cty.GetAttrPath("s3_import").GetAttr("secret_key"),
+		},
+		"google_sql_user": {
+			cty.GetAttrPath("password"),
+			cty.GetAttrPath("token"),
+		},
+	}
+
+	givenPaths := SensitiveAttributePaths(ps)
+	if diff := cmp.Diff(expectedPaths, givenPaths, cmp.Comparer(cty.Path.Equals)); diff != "" {
+		t.Fatalf("sensitive paths mismatch: %s", diff)
+	}
+}
+
+func TestSensitiveAttributePaths_nil(t *testing.T) {
+	paths := SensitiveAttributePaths(nil)
+	if len(paths) != 0 {
+		t.Fatalf("expected no paths, given: %#v", paths)
+	}
+}
@@ -0,0 +1,7 @@
+module "a_missing" {
+  source = "./modules/missing"
+}
+
+module "b_network" {
+  source = "./modules/network"
+}
@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    aws = {
+      source = "mycorp/aws"
+    }
+    google = {
+      source = "mycorp/google"
+    }
+    awsalt = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+module "a_compute" {
+  source = "./modules/shared"
+  providers = {
+    aws = aws
+  }
+}
+
+module "b_network" {
+  source = "./modules/shared"
+  providers = {
+    google = google
+  }
+}
+
+module "c_legacy" {
+  source = "./modules/shared"
+  providers = {
+    aws = awsalt
+  }
+}
@@ -0,0 +1,5 @@
+resource "aws_vpc" "main" {
+}
+
+resource "google_compute_network" "main" {
+}
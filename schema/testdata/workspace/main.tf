@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    aws = {
+      source = "mycorp/aws"
+    }
+  }
+}
+
+provider "aws" {
+}
+
+provider "aws" {
+  alias = "west"
+}
+
+module "network" {
+  source = "./modules/network"
+  providers = {
+    aws = aws.west
+  }
+}
+
+module "storage" {
+  source = "./modules/storage"
+  count  = 2
+}
+
+module "remote" {
+  source = "hashicorp/consul/aws"
+}
@@ -0,0 +1,9 @@
+output "vpc_id" {
+  value       = aws_vpc.main.id
+  description = "ID of the VPC"
+}
+
+output "secret" {
+  value     = var.password
+  sensitive = true
+}
@@ -0,0 +1,14 @@
+variable "cidr_block" {
+  type        = string
+  description = "CIDR block of the VPC"
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+variable "password" {
+  default   = "secret"
+  sensitive = true
+}
@@ -0,0 +1,6 @@
+resource "aws_s3_bucket" "main" {
+}
+
+module "backup" {
+  source = "./backup"
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/module"
+)
+
+// VariablesFileSchema returns the body schema of variable definition
+// files (terraform.tfvars, *.auto.tfvars) of the module represented
+// by the given parsed files, with one attribute per declared variable.
+//
+// The core version (which may be nil) determines how type constraints
+// are decoded, e.g. whether optional object attributes are supported.
+//
+// None of the attributes are required, as variables can also be set
+// via other files, the CLI or environment variables.
+func VariablesFileSchema(files map[string]*hcl.File, coreVersion *version.Version) (*schema.BodySchema, hcl.Diagnostics) {
+	variables, diags := module.DecodeVariablesForVersion(files, coreVersion)
+
+	bs := &schema.BodySchema{
+		Attributes: make(map[string]*schema.AttributeSchema, len(variables)),
+	}
+	for name, v := range variables {
+		bs.Attributes[name] = &schema.AttributeSchema{
+			Description: variableDescription(v),
+			IsOptional:  true,
+			ValueType:   v.Type,
+		}
+	}
+
+	return bs, diags
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVariablesFileSchema(t *testing.T) {
+	src := `variable "name" {
+  type        = string
+  description = "Name of the instance"
+}
+
+variable "ports" {
+  type    = list(number)
+  default = [80]
+}
+
+variable "servers" {
+  type = map(object({
+    size = string
+    tags = optional(map(string))
+  }))
+}
+
+variable "anything" {
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "variables.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	bs, diags := VariablesFileSchema(map[string]*hcl.File{
+		"variables.tf": f,
+	}, version.Must(version.NewVersion("1.3.0")))
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"name": {
+				Description: lang.Markdown("Name of the instance"),
+				IsOptional:  true,
+				ValueType:   cty.String,
+			},
+			"ports": {
+				Description: lang.Markdown("Default: `[80]`"),
+				IsOptional:  true,
+				ValueType:   cty.List(cty.Number),
+			},
+			"servers": {
+				IsOptional: true,
+				ValueType: cty.Map(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+					"size": cty.String,
+					"tags": cty.Map(cty.String),
+				}, []string{"tags"})),
+			},
+			"anything": {
+				IsOptional: true,
+				ValueType:  cty.DynamicPseudoType,
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedSchema, bs, cmp.Comparer(cty.Type.Equals)); diff != "" {
+		t.Fatalf("schema mismatch: %s", diff)
+	}
+}
+
+func TestVariablesFileSchema_optionalAttrsNotSupported(t *testing.T) {
+	src := `variable "server" {
+  type = object({
+    tags = optional(map(string))
+  })
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "variables.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	_, diags = VariablesFileSchema(map[string]*hcl.File{
+		"variables.tf": f,
+	}, version.Must(version.NewVersion("0.13.0")))
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %s", len(diags), diags)
+	}
+}
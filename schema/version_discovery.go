@@ -0,0 +1,246 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/hashicorp/terraform-schema/internal/lockfile"
+	"github.com/hashicorp/terraform-schema/internal/module"
+	"github.com/hashicorp/terraform-schema/providerplugin"
+)
+
+// DiscoveredVersions represents versions of Terraform and providers
+// relevant for a module, as discovered by DiscoverVersions
+type DiscoveredVersions struct {
+	// CoreVersion is the newest known version of Terraform which
+	// satisfies all required_version constraints of the module,
+	// or nil if the constraints cannot be satisfied
+	CoreVersion *version.Version
+
+	// CoreSchema is the core module schema for CoreVersion,
+	// or the universal schema if CoreVersion is nil
+	CoreSchema *schema.BodySchema
+
+	// CoreSchemaExplanation explains how the core schema was chosen,
+	// see CoreSchemaChoice
+	CoreSchemaExplanation string
+
+	// ProviderVersions contains exact versions of providers
+	// keyed by source address, as accepted by SetProviderVersions
+	ProviderVersions map[string]*version.Version
+}
+
+// DiscoverVersions discovers versions of Terraform and providers
+// for the module in the given directory, such that these do not
+// need to be set on the SchemaMerger by hand.
+//
+// The core version is picked based on required_version constraints.
+// Provider versions are read from the dependency lock file
+// (.terraform.lock.hcl) and providers installed in .terraform/providers,
+// where the lock file takes precedence.
+//
+// Diagnostics are returned when constraints cannot be satisfied,
+// in which case the best available versions are still returned.
+func DiscoverVersions(modPath string) (*DiscoveredVersions, hcl.Diagnostics) {
+	files, err := module.ParseFiles(modPath)
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read module",
+				Detail:   fmt.Sprintf("Module directory %q could not be read: %s.", modPath, err),
+			},
+		}
+	}
+
+	req, diags := module.DecodeRequirements(files)
+
+	dv := &DiscoveredVersions{
+		CoreSchema: UniversalCoreModuleSchema(),
+	}
+
+	choice, coreDiags := coreSchemaForRequirements(req.CoreVersion)
+	diags = append(diags, coreDiags...)
+	if choice != nil {
+		dv.CoreVersion = choice.Version
+		dv.CoreSchema = choice.Schema
+		dv.CoreSchemaExplanation = choice.Explanation
+	}
+
+	providerVersions, pDiags := discoverProviderVersions(modPath)
+	diags = append(diags, pDiags...)
+	diags = append(diags, checkProviderVersions(req.Providers, providerVersions)...)
+
+	dv.ProviderVersions = make(map[string]*version.Version, len(providerVersions))
+	for addr, pv := range providerVersions {
+		dv.ProviderVersions[addr.String()] = pv.version
+	}
+
+	return dv, diags
+}
+
+// coreSchemaForRequirements chooses the core schema of the newest
+// known version of Terraform satisfying all given constraints
+func coreSchemaForRequirements(reqs []*module.VersionConstraints) (*CoreSchemaChoice, hcl.Diagnostics) {
+	constraints := make(version.Constraints, 0)
+	for _, vc := range reqs {
+		constraints = append(constraints, vc.Constraints...)
+	}
+
+	choice, err := CoreModuleSchemaForConstraints(constraints, SelectNewest)
+	if err == nil {
+		return choice, nil
+	}
+
+	rawConstraints := make([]string, len(reqs))
+	for i, vc := range reqs {
+		rawConstraints[i] = fmt.Sprintf("%q", vc.Constraints.String())
+	}
+
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unsatisfiable Terraform version constraints",
+		Detail: fmt.Sprintf("No known version of Terraform satisfies the required_version "+
+			"constraints %s.", strings.Join(rawConstraints, ", ")),
+	}
+	if len(reqs) > 0 {
+		diag.Subject = reqs[0].Range.Ptr()
+	}
+
+	return nil, hcl.Diagnostics{diag}
+}
+
+type providerVersion struct {
+	version *version.Version
+	source  string
+}
+
+// discoverProviderVersions reads versions of providers from the lock file
+// and providers installed in .terraform/providers of the given module
+func discoverProviderVersions(modPath string) (map[addrs.Provider]*providerVersion, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	versions := make(map[addrs.Provider]*providerVersion, 0)
+
+	installDir := filepath.Join(".terraform", "providers")
+	installed, err := providerplugin.FindInstalledProviders(filepath.Join(modPath, installDir))
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Failed to read installed providers",
+			Detail:   fmt.Sprintf("Providers installed in %s could not be read: %s.", installDir, err),
+		})
+	}
+	for _, p := range installed {
+		addr, err := addrs.ParseProviderSourceString(p.Address)
+		if err != nil {
+			continue
+		}
+		versions[addr] = &providerVersion{
+			version: p.Version,
+			source:  installDir,
+		}
+	}
+
+	f, lockDiags := parseLockFile(filepath.Join(modPath, lockfile.FileName))
+	diags = append(diags, lockDiags...)
+	if f == nil {
+		return versions, diags
+	}
+
+	lockedVersions, lockDiags := lockfile.DecodeProviderVersions(f)
+	diags = append(diags, lockDiags...)
+
+	for _, addr := range sortedProviderAddrs(lockedVersions) {
+		v := lockedVersions[addr]
+		if pv, ok := versions[addr]; ok && !pv.version.Equal(v) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Installed provider does not match lock file",
+				Detail: fmt.Sprintf("Provider %s %s is installed in %s, but version %s is locked in %s. "+
+					"Run \"terraform init\" to install the locked version.",
+					addr, pv.version, installDir, v, lockfile.FileName),
+			})
+		}
+		versions[addr] = &providerVersion{
+			version: v,
+			source:  lockfile.FileName,
+		}
+	}
+
+	return versions, diags
+}
+
+func parseLockFile(path string) (*hcl.File, hcl.Diagnostics) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read dependency lock file",
+				Detail:   fmt.Sprintf("%s could not be read: %s.", lockfile.FileName, err),
+			},
+		}
+	}
+
+	return hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+}
+
+// checkProviderVersions reports providers whose discovered versions
+// do not satisfy the version constraints declared in required_providers
+func checkProviderVersions(reqs map[addrs.Provider][]*module.VersionConstraints,
+	versions map[addrs.Provider]*providerVersion) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	addrList := make([]addrs.Provider, 0, len(reqs))
+	for addr := range reqs {
+		addrList = append(addrList, addr)
+	}
+	sort.Slice(addrList, func(i, j int) bool {
+		return addrList[i].String() < addrList[j].String()
+	})
+
+	for _, addr := range addrList {
+		pv, ok := versions[addr]
+		if !ok {
+			continue
+		}
+		for _, vc := range reqs[addr] {
+			if vc.Constraints.Check(pv.version) {
+				continue
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsatisfied provider version constraints",
+				Detail: fmt.Sprintf("Provider %s %s selected in %s does not satisfy the version "+
+					"constraints %q. Run \"terraform init -upgrade\" to select a matching version.",
+					addr, pv.version, pv.source, vc.Constraints.String()),
+				Subject: vc.Range.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+func sortedProviderAddrs(m map[addrs.Provider]*version.Version) []addrs.Provider {
+	addrList := make([]addrs.Provider, 0, len(m))
+	for addr := range m {
+		addrList = append(addrList, addr)
+	}
+	sort.Slice(addrList, func(i, j int) bool {
+		return addrList[i].String() < addrList[j].String()
+	})
+	return addrList
+}
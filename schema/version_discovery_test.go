@@ -0,0 +1,239 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestDiscoverVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bit is not available on Windows")
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	awsInstallPath := ".terraform/providers/registry.terraform.io/hashicorp/aws/3.20.0/" +
+		platform + "/terraform-provider-aws_v3.20.0_x5"
+
+	type diag struct {
+		Severity hcl.DiagnosticSeverity
+		Summary  string
+	}
+
+	testCases := []struct {
+		name                     string
+		files                    map[string]string
+		expectedCoreVersion      string
+		expectedProviderVersions map[string]string
+		expectedDiags            []diag
+	}{
+		{
+			"no constraints",
+			map[string]string{
+				"main.tf": `variable "foo" {}`,
+			},
+			"1.3.0",
+			map[string]string{},
+			[]diag{},
+		},
+		{
+			"version range",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = ">= 0.13, < 0.15"
+}`,
+			},
+			"0.14.0",
+			map[string]string{},
+			[]diag{},
+		},
+		{
+			"multiple constraints",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = ">= 0.12"
+}`,
+				"versions.tf": `terraform {
+  required_version = "~> 0.12.7"
+}`,
+			},
+			"0.12.20",
+			map[string]string{},
+			[]diag{},
+		},
+		{
+			"pinned version",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = "0.12.10"
+}`,
+			},
+			"0.12.10",
+			map[string]string{},
+			[]diag{},
+		},
+		{
+			"unsatisfiable constraints",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = "< 0.11"
+}`,
+			},
+			"",
+			map[string]string{},
+			[]diag{
+				{hcl.DiagError, "Unsatisfiable Terraform version constraints"},
+			},
+		},
+		{
+			"invalid constraint",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = "foo"
+}`,
+			},
+			"1.3.0",
+			map[string]string{},
+			[]diag{
+				{hcl.DiagError, "Invalid version constraint"},
+			},
+		},
+		{
+			"installed providers",
+			map[string]string{
+				"main.tf":      `provider "aws" {}`,
+				awsInstallPath: "",
+			},
+			"1.3.0",
+			map[string]string{
+				"registry.terraform.io/hashicorp/aws": "3.20.0",
+			},
+			[]diag{},
+		},
+		{
+			"lock file takes precedence",
+			map[string]string{
+				"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+  }
+}`,
+				".terraform.lock.hcl": `provider "registry.terraform.io/hashicorp/aws" {
+  version = "3.27.0"
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.0.1"
+}
+`,
+				awsInstallPath: "",
+			},
+			"1.3.0",
+			map[string]string{
+				"registry.terraform.io/hashicorp/aws":    "3.27.0",
+				"registry.terraform.io/hashicorp/random": "3.0.1",
+			},
+			[]diag{
+				{hcl.DiagWarning, "Installed provider does not match lock file"},
+			},
+		},
+		{
+			"unsatisfied provider constraints",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = "~> 0.12.0"
+  required_providers {
+    aws = "~> 2.0"
+  }
+}`,
+				".terraform.lock.hcl": `provider "registry.terraform.io/hashicorp/aws" {
+  version = "3.27.0"
+}
+`,
+			},
+			"0.12.20",
+			map[string]string{
+				"registry.terraform.io/hashicorp/aws": "3.27.0",
+			},
+			[]diag{
+				{hcl.DiagError, "Unsatisfied provider version constraints"},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "discovery")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			for path, src := range tc.files {
+				fullPath := filepath.Join(dir, filepath.FromSlash(path))
+				err := os.MkdirAll(filepath.Dir(fullPath), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = ioutil.WriteFile(fullPath, []byte(src), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dv, diags := DiscoverVersions(dir)
+			if dv == nil {
+				t.Fatalf("expected discovered versions, diagnostics: %s", diags)
+			}
+
+			givenDiags := make([]diag, len(diags))
+			for i, d := range diags {
+				givenDiags[i] = diag{d.Severity, d.Summary}
+			}
+			if diff := cmp.Diff(tc.expectedDiags, givenDiags); diff != "" {
+				t.Fatalf("diagnostics mismatch: %s", diff)
+			}
+
+			coreVersion := ""
+			if dv.CoreVersion != nil {
+				coreVersion = dv.CoreVersion.String()
+			}
+			if coreVersion != tc.expectedCoreVersion {
+				t.Fatalf("core version mismatch: expected %q, given %q",
+					tc.expectedCoreVersion, coreVersion)
+			}
+			if dv.CoreSchema == nil {
+				t.Fatal("expected core schema")
+			}
+
+			providerVersions := make(map[string]string, len(dv.ProviderVersions))
+			for addr, v := range dv.ProviderVersions {
+				providerVersions[addr] = v.String()
+			}
+			if diff := cmp.Diff(tc.expectedProviderVersions, providerVersions); diff != "" {
+				t.Fatalf("provider versions mismatch: %s", diff)
+			}
+
+			sm := NewSchemaMerger(dv.CoreSchema)
+			if err := sm.SetProviderVersions(dv.ProviderVersions); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestDiscoverVersions_missingDir(t *testing.T) {
+	_, diags := DiscoverVersions(filepath.Join("testdata", "does-not-exist"))
+	if !diags.HasErrors() {
+		t.Fatal("expected error for missing directory")
+	}
+}
@@ -0,0 +1,260 @@
+package schema
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-schema/internal/addrs"
+	"github.com/hashicorp/terraform-schema/internal/module"
+	"github.com/hashicorp/terraform-schema/internal/refdecoder"
+)
+
+// WorkspaceSchemaMerger merges schemas for a root module
+// and all local modules called from it (recursively)
+type WorkspaceSchemaMerger struct {
+	coreSchema *schema.BodySchema
+
+	coreVersion      *version.Version
+	providerVersions map[string]*version.Version
+	schemaLoader     ProviderSchemaLoader
+}
+
+// NewWorkspaceSchemaMerger creates a new workspace merger
+// for the given core schema
+func NewWorkspaceSchemaMerger(coreSchema *schema.BodySchema) *WorkspaceSchemaMerger {
+	return &WorkspaceSchemaMerger{
+		coreSchema: coreSchema,
+	}
+}
+
+// SetCoreVersion sets version of Terraform (core),
+// see SchemaMerger.SetCoreVersion
+func (w *WorkspaceSchemaMerger) SetCoreVersion(v *version.Version) {
+	w.coreVersion = v
+}
+
+// SetProviderVersions sets versions of providers,
+// see SchemaMerger.SetProviderVersions
+func (w *WorkspaceSchemaMerger) SetProviderVersions(versions map[string]*version.Version) error {
+	for addr := range versions {
+		_, err := addrs.ParseProviderSourceString(addr)
+		if err != nil {
+			return err
+		}
+	}
+	w.providerVersions = versions
+	return nil
+}
+
+// SetProviderSchemaLoader sets a loader for provider schemas,
+// see SchemaMerger.SetProviderSchemaLoader
+func (w *WorkspaceSchemaMerger) SetProviderSchemaLoader(l ProviderSchemaLoader) {
+	w.schemaLoader = l
+}
+
+// MergeWithJsonProviderSchemas walks the root module in rootDir
+// and any modules called from it which have local source (e.g. ./modules/foo)
+// and returns merged schema for each module, keyed by module directory.
+//
+// Local provider names of each child module which are not declared
+// in its required_providers are resolved via the providers argument
+// of the module call, or default (unaliased) provider configurations
+// of the parent module if the argument is not present.
+//
+// If a module directory is called more than once, providers passed
+// in all calls are combined, such that the merged schema reflects each
+// of them. Where calls pass different providers under the same local name,
+// the first call (in order of depth-first traversal, with calls sorted
+// by name) takes precedence and a warning is reported.
+//
+// Any parsing errors in individual files are ignored
+// to allow merging of schemas for incomplete configuration.
+// Failures to merge schema of a child module (e.g. one which does not
+// exist yet) are reported as diagnostics and the remaining modules
+// are still merged. An error is only returned if schema
+// of the root module cannot be merged.
+func (w *WorkspaceSchemaMerger) MergeWithJsonProviderSchemas(rootDir string, ps *tfjson.ProviderSchemas) (map[string]*schema.BodySchema, hcl.Diagnostics, error) {
+	if w.coreSchema == nil {
+		return nil, nil, coreSchemaRequiredErr{}
+	}
+
+	wm := &workspaceMerge{
+		ps:        ps,
+		schemas:   make(map[string]*schema.BodySchema, 0),
+		providers: make(map[string]map[string]addrs.Provider, 0),
+		diags:     make(map[string]hcl.Diagnostics, 0),
+	}
+
+	_, err := w.mergeModule(wm, filepath.Clean(rootDir), nil)
+	if err != nil {
+		return nil, wm.diagnostics(), err
+	}
+
+	return wm.schemas, wm.diagnostics(), nil
+}
+
+// workspaceMerge holds state of a single workspace merge
+type workspaceMerge struct {
+	ps      *tfjson.ProviderSchemas
+	schemas map[string]*schema.BodySchema
+
+	// providers tracks providers passed to each merged module
+	// (keyed by directory), combined across all calls of it
+	providers map[string]map[string]addrs.Provider
+
+	// diags tracks diagnostics of the last merge of each module
+	// (keyed by directory), in order of first merge
+	dirs  []string
+	diags map[string]hcl.Diagnostics
+}
+
+func (wm *workspaceMerge) diagnostics() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, dir := range wm.dirs {
+		diags = append(diags, wm.diags[dir]...)
+	}
+	return diags
+}
+
+// mergeModule merges schema of the module in dir and then (recursively)
+// of any local modules called from it, where failures of the latter
+// are recorded as diagnostics, rather than returned.
+//
+// A module which was already merged is merged again only if the call
+// passes providers under local names not passed by previous calls.
+// Returned diagnostics relate to the call and belong to the caller.
+func (w *WorkspaceSchemaMerger) mergeModule(wm *workspaceMerge, dir string, inherited map[string]addrs.Provider) (hcl.Diagnostics, error) {
+	previous, merged := wm.providers[dir]
+	providers, callDiags, changed := combineProviders(dir, previous, inherited)
+	if merged && !changed {
+		return callDiags, nil
+	}
+	if !merged {
+		wm.dirs = append(wm.dirs, dir)
+	}
+	wm.providers[dir] = providers
+
+	files, err := module.ParseFiles(dir)
+	if err != nil {
+		return callDiags, err
+	}
+
+	sm := NewSchemaMerger(w.coreSchema)
+	sm.SetParsedFiles(files)
+	sm.SetCoreVersion(w.coreVersion)
+	if w.providerVersions != nil {
+		err = sm.SetProviderVersions(w.providerVersions)
+		if err != nil {
+			return callDiags, err
+		}
+	}
+	if w.schemaLoader != nil {
+		sm.SetProviderSchemaLoader(w.schemaLoader)
+	}
+	sm.SetModulePath(dir)
+	sm.inheritedProviders = providers
+
+	mergedSchema, diags, err := sm.MergeWithJsonProviderSchemas(wm.ps)
+	wm.diags[dir] = diags
+	if err != nil {
+		return callDiags, err
+	}
+	wm.schemas[dir] = mergedSchema
+
+	refs, _ := refdecoder.DecodeProviderReferencesWithInherited(files, providers)
+	calls, _ := refdecoder.DecodeModuleCalls(files)
+
+	for _, call := range calls {
+		if !module.IsLocalSource(call.Source) {
+			continue
+		}
+		childDir := filepath.Join(dir, filepath.FromSlash(call.Source))
+		childDiags, err := w.mergeModule(wm, childDir, childProviders(call, refs))
+		diags = append(diags, childDiags...)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to merge module schema",
+				Detail: fmt.Sprintf("Schema of module %q (%s) called from %s could not be merged: %s.",
+					call.Name, childDir, dir, err),
+			})
+		}
+	}
+	wm.diags[dir] = diags
+
+	return callDiags, nil
+}
+
+// combineProviders adds providers passed in a call of the module in dir
+// to the providers passed in previous calls (if any) and returns
+// the combined providers, along with warnings about providers
+// conflicting with previous calls and whether any new ones were added
+func combineProviders(dir string, previous, passed map[string]addrs.Provider) (map[string]addrs.Provider, hcl.Diagnostics, bool) {
+	var diags hcl.Diagnostics
+	changed := false
+
+	combined := make(map[string]addrs.Provider, len(previous)+len(passed))
+	for name, addr := range previous {
+		combined[name] = addr
+	}
+
+	names := make([]string, 0, len(passed))
+	for name := range passed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		addr := passed[name]
+		existing, ok := combined[name]
+		if !ok {
+			combined[name] = addr
+			changed = true
+			continue
+		}
+		if existing != addr {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Conflicting providers passed to module",
+				Detail: fmt.Sprintf("Module in %s is called with both %s and %s as provider %q. "+
+					"Schema of %s is used for all calls.",
+					dir, existing.ForDisplay(), addr.ForDisplay(), name, existing.ForDisplay()),
+			})
+		}
+	}
+
+	return combined, diags, changed
+}
+
+// childProviders returns providers passed to the child module
+// in the given module call, keyed by the child's local names
+func childProviders(call *refdecoder.ModuleCall, parentRefs addrs.ProviderReferences) map[string]addrs.Provider {
+	providers := make(map[string]addrs.Provider, 0)
+
+	if !call.HasProviders {
+		for ref, addr := range parentRefs {
+			if ref.Alias == "" {
+				providers[ref.LocalName] = addr
+			}
+		}
+		return providers
+	}
+
+	for childRef, parentRef := range call.Providers {
+		addr, ok := parentRefs[parentRef]
+		if !ok {
+			addr, ok = parentRefs[addrs.LocalProviderConfig{LocalName: parentRef.LocalName}]
+		}
+		if !ok {
+			addr = addrs.ImpliedProviderForUnqualifiedType(parentRef.LocalName)
+		}
+		providers[childRef.LocalName] = addr
+	}
+
+	return providers
+}
@@ -0,0 +1,213 @@
+package schema
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestWorkspaceSchemaMerger_noCoreSchema(t *testing.T) {
+	wm := NewWorkspaceSchemaMerger(nil)
+
+	_, _, err := wm.MergeWithJsonProviderSchemas(filepath.Join("testdata", "workspace"), nil)
+	if err == nil {
+		t.Fatal("expected error for nil core schema")
+	}
+}
+
+func TestWorkspaceSchemaMerger_MergeWithJsonProviderSchemas(t *testing.T) {
+	resourceSchema := func(attrName string) *tfjson.Schema {
+		return &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					attrName: {AttributeType: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/mycorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_vpc":       resourceSchema("mycorp_attr"),
+					"aws_s3_bucket": resourceSchema("mycorp_attr"),
+				},
+			},
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_vpc":       resourceSchema("hashicorp_attr"),
+					"aws_s3_bucket": resourceSchema("hashicorp_attr"),
+				},
+			},
+		},
+	}
+
+	rootDir := filepath.Join("testdata", "workspace")
+	wm := NewWorkspaceSchemaMerger(testCoreSchema)
+	schemas, diags, err := wm.MergeWithJsonProviderSchemas(rootDir, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	expectedDirs := []string{
+		rootDir,
+		filepath.Join(rootDir, "modules", "network"),
+		filepath.Join(rootDir, "modules", "storage"),
+		filepath.Join(rootDir, "modules", "storage", "backup"),
+	}
+	givenDirs := make([]string, 0, len(schemas))
+	for dir := range schemas {
+		givenDirs = append(givenDirs, dir)
+	}
+	sort.Strings(expectedDirs)
+	sort.Strings(givenDirs)
+	if diff := cmp.Diff(expectedDirs, givenDirs); diff != "" {
+		t.Fatalf("module directories mismatch: %s", diff)
+	}
+
+	testCases := []struct {
+		dir          string
+		resourceType string
+	}{
+		{filepath.Join(rootDir, "modules", "network"), "aws_vpc"},
+		{filepath.Join(rootDir, "modules", "storage"), "aws_s3_bucket"},
+		{filepath.Join(rootDir, "modules", "storage", "backup"), "aws_s3_bucket"},
+	}
+	for _, tc := range testCases {
+		bs, ok := schemas[tc.dir].Blocks["resource"].DependentBodySchema(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: tc.resourceType},
+			},
+		})
+		if !ok {
+			t.Fatalf("%s: expected schema for %s", tc.dir, tc.resourceType)
+		}
+		if _, ok := bs.Attributes["mycorp_attr"]; !ok {
+			t.Fatalf("%s: expected schema of mycorp/aws provider for %s, given %q",
+				tc.dir, tc.resourceType, bs.Detail)
+		}
+	}
+}
+
+func TestWorkspaceSchemaMerger_MergeWithJsonProviderSchemas_sharedModule(t *testing.T) {
+	resourceSchema := func(attrName string) *tfjson.Schema {
+		return &tfjson.Schema{
+			Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{
+					attrName: {AttributeType: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	ps := &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/mycorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_vpc": resourceSchema("mycorp_attr"),
+				},
+			},
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"aws_vpc": resourceSchema("hashicorp_attr"),
+				},
+			},
+			"registry.terraform.io/mycorp/google": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_compute_network": resourceSchema("mycorp_attr"),
+				},
+			},
+			"registry.terraform.io/hashicorp/google": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"google_compute_network": resourceSchema("hashicorp_attr"),
+				},
+			},
+		},
+	}
+
+	rootDir := filepath.Join("testdata", "workspace-shared")
+	sharedDir := filepath.Join(rootDir, "modules", "shared")
+	wm := NewWorkspaceSchemaMerger(testCoreSchema)
+	schemas, diags, err := wm.MergeWithJsonProviderSchemas(rootDir, ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "Conflicting providers passed to module",
+			Detail: fmt.Sprintf("Module in %s is called with both mycorp/aws and hashicorp/aws "+
+				"as provider \"aws\". Schema of mycorp/aws is used for all calls.", sharedDir),
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+
+	// providers passed by each call are reflected
+	for _, resourceType := range []string{"aws_vpc", "google_compute_network"} {
+		bs, ok := schemas[sharedDir].Blocks["resource"].DependentBodySchema(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: 0, Value: resourceType},
+			},
+		})
+		if !ok {
+			t.Fatalf("expected schema for %s", resourceType)
+		}
+		if _, ok := bs.Attributes["mycorp_attr"]; !ok {
+			t.Fatalf("expected schema of mycorp provider for %s, given %q",
+				resourceType, bs.Detail)
+		}
+	}
+}
+
+func TestWorkspaceSchemaMerger_MergeWithJsonProviderSchemas_childModuleFailure(t *testing.T) {
+	rootDir := filepath.Join("testdata", "workspace-partial")
+	wm := NewWorkspaceSchemaMerger(testCoreSchema)
+	schemas, diags, err := wm.MergeWithJsonProviderSchemas(rootDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDirs := []string{
+		rootDir,
+		filepath.Join(rootDir, "modules", "network"),
+	}
+	givenDirs := make([]string, 0, len(schemas))
+	for dir := range schemas {
+		givenDirs = append(givenDirs, dir)
+	}
+	sort.Strings(givenDirs)
+	if diff := cmp.Diff(expectedDirs, givenDirs); diff != "" {
+		t.Fatalf("module directories mismatch: %s", diff)
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly 1 diagnostic, given: %#v", diags)
+	}
+	if diags[0].Severity != hcl.DiagError {
+		t.Fatalf("expected error diagnostic, given: %#v", diags[0])
+	}
+	if !strings.Contains(diags[0].Detail, `module "a_missing"`) {
+		t.Fatalf("expected diagnostic for a_missing module, given: %q", diags[0].Detail)
+	}
+}
+
+func TestWorkspaceSchemaMerger_MergeWithJsonProviderSchemas_rootModuleFailure(t *testing.T) {
+	wm := NewWorkspaceSchemaMerger(testCoreSchema)
+	_, _, err := wm.MergeWithJsonProviderSchemas(filepath.Join("testdata", "missing"), nil)
+	if err == nil {
+		t.Fatal("expected error for missing root module")
+	}
+}
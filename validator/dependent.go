@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// bodySchemaForBlock returns the schema to validate the body
+// of the given block with and whether the schema is complete,
+// i.e. whether unknown attributes and blocks can be reported.
+//
+// The schema is incomplete when the block declares dependency keys
+// but none of its dependent bodies match, e.g. resources of unknown
+// providers or modules from remote sources.
+func bodySchemaForBlock(block *hcl.Block, bSchema *schema.BlockSchema) (*schema.BodySchema, bool) {
+	if !hasDependencyKeys(bSchema) {
+		return bSchema.Body, true
+	}
+
+	depBody, ok := dependentBodySchema(block, bSchema)
+	if !ok {
+		return bSchema.Body, false
+	}
+
+	return mergeBodySchemas(bSchema.Body, depBody), true
+}
+
+func hasDependencyKeys(bSchema *schema.BlockSchema) bool {
+	if len(bSchema.DependentBody) > 0 {
+		return true
+	}
+	for _, label := range bSchema.Labels {
+		if label.IsDepKey {
+			return true
+		}
+	}
+	if bSchema.Body != nil {
+		for _, attr := range bSchema.Body.Attributes {
+			if attr.IsDepKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependentBodySchema looks up the dependent body using labels
+// and attributes of the block which are marked as dependency keys.
+//
+// Keys without attributes are also tried, as the schema merger keys
+// resources on type alone where the provider meta-argument
+// refers to the implied provider.
+//
+// If the dependent body declares dependency keys of its own
+// (e.g. backend of terraform_remote_state, which is not shared
+// by other data sources), the body is looked up again
+// with these keys included.
+func dependentBodySchema(block *hcl.Block, bSchema *schema.BlockSchema) (*schema.BodySchema, bool) {
+	keys := schema.DependencyKeys{}
+
+	for i, label := range bSchema.Labels {
+		if label.IsDepKey && i < len(block.Labels) {
+			keys.Labels = append(keys.Labels, schema.LabelDependent{
+				Index: i,
+				Value: block.Labels[i],
+			})
+		}
+	}
+
+	keys.Attributes = attributeDependents(block, bSchema.Body, nil)
+
+	bs, ok := bSchema.DependentBodySchema(keys)
+	if !ok && len(keys.Attributes) > 0 && len(keys.Labels) > 0 {
+		keys.Attributes = nil
+		bs, ok = bSchema.DependentBodySchema(keys)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	nestedAttrs := attributeDependents(block, bs, bSchema.Body)
+	if len(nestedAttrs) == 0 {
+		return bs, true
+	}
+	keys.Attributes = append(keys.Attributes, nestedAttrs...)
+	if nestedBs, ok := bSchema.DependentBodySchema(keys); ok {
+		return nestedBs, true
+	}
+
+	return bs, true
+}
+
+// attributeDependents returns dependency keys for attributes
+// of the block which are declared as such in the given body schema,
+// except for those declared in the parent body schema (if any)
+func attributeDependents(block *hcl.Block, bs, parent *schema.BodySchema) []schema.AttributeDependent {
+	if bs == nil {
+		return nil
+	}
+
+	hclSchema := &hcl.BodySchema{}
+	for _, name := range sortedAttributeSchemaNames(bs.Attributes) {
+		if !bs.Attributes[name].IsDepKey {
+			continue
+		}
+		if parent != nil {
+			if attr, ok := parent.Attributes[name]; ok && attr.IsDepKey {
+				continue
+			}
+		}
+		hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{Name: name})
+	}
+	if len(hclSchema.Attributes) == 0 {
+		return nil
+	}
+
+	var attrs []schema.AttributeDependent
+	content, _, _ := block.Body.PartialContent(hclSchema)
+	for _, attrSchema := range hclSchema.Attributes {
+		attr, ok := content.Attributes[attrSchema.Name]
+		if !ok {
+			continue
+		}
+		ev, ok := expressionValue(attr.Expr)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, schema.AttributeDependent{
+			Name: attr.Name,
+			Expr: ev,
+		})
+	}
+
+	return attrs
+}
+
+// expressionValue returns a static value of the expression if it
+// is known without evaluation context, or a reference otherwise
+func expressionValue(expr hcl.Expression) (schema.ExpressionValue, bool) {
+	if len(expr.Variables()) == 0 {
+		val, diags := expr.Value(nil)
+		if !diags.HasErrors() && val.IsWhollyKnown() {
+			return schema.ExpressionValue{
+				Static: val,
+			}, true
+		}
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return schema.ExpressionValue{}, false
+	}
+
+	ref := make(lang.Reference, 0, len(traversal))
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			ref = append(ref, lang.RootStep{Name: s.Name})
+		case hcl.TraverseAttr:
+			ref = append(ref, lang.AttrStep{Name: s.Name})
+		case hcl.TraverseIndex:
+			ref = append(ref, lang.IndexStep{Key: s.Key})
+		default:
+			return schema.ExpressionValue{}, false
+		}
+	}
+
+	return schema.ExpressionValue{
+		Reference: ref,
+	}, true
+}
+
+// mergeBodySchemas returns a new body schema combining
+// attributes and blocks of both schemas, dependent ones
+// taking precedence
+func mergeBodySchemas(base, dependent *schema.BodySchema) *schema.BodySchema {
+	merged := schema.NewBodySchema()
+	for _, bs := range []*schema.BodySchema{base, dependent} {
+		if bs == nil {
+			continue
+		}
+		for name, attr := range bs.Attributes {
+			merged.Attributes[name] = attr
+		}
+		for bType, block := range bs.Blocks {
+			merged.Blocks[bType] = block
+		}
+		if bs.AnyAttribute != nil {
+			merged.AnyAttribute = bs.AnyAttribute
+		}
+	}
+	merged.IsDeprecated = dependent.IsDeprecated
+
+	return merged
+}
+
+// dynamicBodySchema returns the schema of a dynamic block
+// generating blocks of the given schema
+func dynamicBodySchema(bSchema *schema.BlockSchema) *schema.BodySchema {
+	return &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"for_each": {
+				ValueType:  cty.DynamicPseudoType,
+				IsRequired: true,
+			},
+			"iterator": {
+				ValueType:  cty.DynamicPseudoType,
+				IsOptional: true,
+			},
+			"labels": {
+				ValueType:  cty.List(cty.String),
+				IsOptional: true,
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"content": {
+				Body:          bSchema.Body,
+				DependentBody: bSchema.DependentBody,
+				MinItems:      1,
+				MaxItems:      1,
+			},
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+)
+
+// declareUnavailableFeatures adds attributes and blocks which are
+// not available in the targeted version of Terraform to the given
+// HCL schema, so they can be reported as such, rather than as unknown
+func (v *Validator) declareUnavailableFeatures(hclSchema *hcl.BodySchema, bs *schema.BodySchema,
+	featureAddrs []string) (map[string]*tfschema.Feature, map[string]*tfschema.Feature) {
+	attrs := make(map[string]*tfschema.Feature, 0)
+	blocks := make(map[string]*tfschema.Feature, 0)
+
+	for _, addr := range featureAddrs {
+		for _, f := range v.unavailableFeatures[addr] {
+			switch f.Kind {
+			case tfschema.FeatureAttribute:
+				if _, ok := bs.Attributes[f.Name]; ok {
+					continue
+				}
+				if _, ok := attrs[f.Name]; ok {
+					continue
+				}
+				attrs[f.Name] = f
+				hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{
+					Name: f.Name,
+				})
+			case tfschema.FeatureBlock:
+				if _, ok := bs.Blocks[f.Name]; ok {
+					continue
+				}
+				if _, ok := blocks[f.Name]; ok {
+					continue
+				}
+				blocks[f.Name] = f
+				hclSchema.Blocks = append(hclSchema.Blocks, hcl.BlockHeaderSchema{
+					Type:       f.Name,
+					LabelNames: f.LabelNames,
+				})
+			}
+		}
+	}
+
+	return attrs, blocks
+}
+
+// unavailableDependentBody returns a dependent body (such as
+// a provisioner) which the block refers to via its label
+// if it is not available in the targeted version of Terraform
+func (v *Validator) unavailableDependentBody(block *hcl.Block, bSchema *schema.BlockSchema,
+	featureAddrs []string) (*tfschema.Feature, hcl.Range, bool) {
+	for i, label := range bSchema.Labels {
+		if !label.IsDepKey || i >= len(block.Labels) {
+			continue
+		}
+		for _, addr := range featureAddrs {
+			for _, f := range v.unavailableFeatures[addr] {
+				if f.Kind == tfschema.FeatureDependentBody && f.Name == block.Labels[i] {
+					return f, block.LabelRanges[i], true
+				}
+			}
+		}
+	}
+	return nil, hcl.Range{}, false
+}
+
+func (v *Validator) unavailableFeatureDiagnostic(f *tfschema.Feature, rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unavailable language feature",
+		Detail:   f.UnavailableDetail(v.coreVersion) + ".",
+		Subject:  rng.Ptr(),
+	}
+}
+
+func childFeatureAddresses(featureAddrs []string, name string) []string {
+	addrs := make([]string, len(featureAddrs))
+	for i, addr := range featureAddrs {
+		addrs[i] = tfschema.FeatureAddress(addr, name)
+	}
+	return addrs
+}
+
+// dependentFeatureAddresses returns addresses of dependent
+// bodies keyed on labels of the given block
+func dependentFeatureAddresses(block *hcl.Block, bSchema *schema.BlockSchema, featureAddrs []string) []string {
+	addrs := make([]string, 0)
+	for i, label := range bSchema.Labels {
+		if !label.IsDepKey || i >= len(block.Labels) {
+			continue
+		}
+		for _, addr := range featureAddrs {
+			addrs = append(addrs, tfschema.DependentBodyFeatureAddress(addr, block.Labels[i]))
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,364 @@
+// Package validator provides static validation of Terraform
+// configuration against a body schema, such as one produced
+// by SchemaMerger, without running Terraform.
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/internal/typeexpr"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+type Validator struct {
+	bodySchema *schema.BodySchema
+
+	coreVersion *version.Version
+	// unavailableFeatures are core features not available
+	// in coreVersion, keyed by address of their parent body
+	unavailableFeatures map[string][]*tfschema.Feature
+}
+
+func NewValidator(bodySchema *schema.BodySchema) *Validator {
+	return &Validator{
+		bodySchema: bodySchema,
+	}
+}
+
+// SetCoreVersion enables reporting of attributes and blocks
+// which are not available in the given version of Terraform,
+// such as for_each on module blocks before 0.13, instead of
+// reporting them as unknown
+func (v *Validator) SetCoreVersion(coreVersion *version.Version) {
+	v.coreVersion = coreVersion
+	v.unavailableFeatures = make(map[string][]*tfschema.Feature, 0)
+
+	for _, f := range tfschema.CoreFeatureTable().Features() {
+		if !f.IsAvailable(coreVersion) {
+			v.unavailableFeatures[f.Parent] = append(v.unavailableFeatures[f.Parent], f)
+		}
+	}
+}
+
+// ValidateFiles validates bodies of the given files,
+// ordered by file name
+func (v *Validator) ValidateFiles(files map[string]*hcl.File) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := files[name]
+		if f == nil || f.Body == nil {
+			continue
+		}
+		diags = append(diags, v.ValidateBody(f.Body)...)
+	}
+
+	return diags
+}
+
+// ValidateBody validates the given body against the schema.
+//
+// Bodies of blocks are validated against dependent bodies where
+// these can be resolved. Unknown attributes and blocks are not
+// reported for blocks whose dependent body is not known (e.g.
+// resources of providers without schema), as only the parts
+// of the body which are known are validated.
+func (v *Validator) ValidateBody(body hcl.Body) hcl.Diagnostics {
+	if v.bodySchema == nil {
+		return nil
+	}
+	return v.validateBody(body, v.bodySchema, []string{""}, true, false)
+}
+
+// validateBody validates the body against the schema.
+// Feature addresses identify the body in the feature table,
+// which may be more than one for bodies merged with dependent ones.
+func (v *Validator) validateBody(body hcl.Body, bs *schema.BodySchema, featureAddrs []string, strict, nested bool) hcl.Diagnostics {
+	hclSchema := hclBodySchema(bs, nested)
+	unavailableAttrs, unavailableBlocks := v.declareUnavailableFeatures(hclSchema, bs, featureAddrs)
+
+	var content *hcl.BodyContent
+	var diags hcl.Diagnostics
+	if strict && bs.AnyAttribute == nil {
+		content, diags = body.Content(hclSchema)
+	} else {
+		var remain hcl.Body
+		content, remain, diags = body.PartialContent(hclSchema)
+		if bs.AnyAttribute != nil {
+			attrs, attrDiags := remain.JustAttributes()
+			if strict {
+				diags = append(diags, attrDiags...)
+			}
+			for name, attr := range attrs {
+				content.Attributes[name] = attr
+			}
+		}
+	}
+
+	for _, name := range sortedAttributeNames(content.Attributes) {
+		attr := content.Attributes[name]
+		if f, ok := unavailableAttrs[name]; ok {
+			diags = append(diags, v.unavailableFeatureDiagnostic(f, attr.NameRange))
+			continue
+		}
+		aSchema, ok := bs.Attributes[name]
+		if !ok {
+			aSchema = bs.AnyAttribute
+		}
+		diags = append(diags, validateAttribute(attr, aSchema)...)
+	}
+
+	blocks := make(map[string]hcl.Blocks, 0)
+	dynamicTypes := make(map[string]bool, 0)
+	for _, block := range content.Blocks {
+		if f, ok := unavailableBlocks[block.Type]; ok {
+			diags = append(diags, v.unavailableFeatureDiagnostic(f, block.DefRange))
+			continue
+		}
+		if _, ok := bs.Blocks[block.Type]; !ok && block.Type == "dynamic" {
+			bType := block.Labels[0]
+			bSchema, ok := bs.Blocks[bType]
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported block type",
+					Detail:   fmt.Sprintf("Blocks of type %q are not expected here, so they cannot be generated dynamically.", bType),
+					Subject:  block.LabelRanges[0].Ptr(),
+				})
+				continue
+			}
+			dynamicTypes[bType] = true
+			diags = append(diags, v.validateBody(block.Body, dynamicBodySchema(bSchema),
+				childFeatureAddresses(featureAddrs, bType), true, true)...)
+			continue
+		}
+		blocks[block.Type] = append(blocks[block.Type], block)
+	}
+
+	for _, bType := range sortedBlockTypes(bs.Blocks) {
+		bSchema := bs.Blocks[bType]
+		typeBlocks := blocks[bType]
+
+		// the number of dynamically generated blocks is not known
+		if !dynamicTypes[bType] {
+			diags = append(diags, validateBlockCount(body, bType, bSchema, typeBlocks)...)
+		}
+
+		for _, block := range typeBlocks {
+			diags = append(diags, v.validateBlock(block, bSchema, childFeatureAddresses(featureAddrs, bType))...)
+		}
+	}
+
+	return diags
+}
+
+func validateBlockCount(body hcl.Body, bType string, bSchema *schema.BlockSchema, blocks hcl.Blocks) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if bSchema.MinItems > 0 && uint64(len(blocks)) < bSchema.MinItems {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Insufficient %s blocks", bType),
+			Detail:   fmt.Sprintf("At least %d %q blocks are required.", bSchema.MinItems, bType),
+			Subject:  body.MissingItemRange().Ptr(),
+		})
+	}
+	if bSchema.MaxItems > 0 && uint64(len(blocks)) > bSchema.MaxItems {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Too many %s blocks", bType),
+			Detail:   fmt.Sprintf("No more than %d %q blocks are allowed.", bSchema.MaxItems, bType),
+			Subject:  blocks[bSchema.MaxItems].DefRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func (v *Validator) validateBlock(block *hcl.Block, bSchema *schema.BlockSchema, featureAddrs []string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if bSchema.IsDeprecated {
+		diags = append(diags, deprecatedBlockDiagnostic(block))
+	}
+
+	bodySchema, strict := bodySchemaForBlock(block, bSchema)
+	if !strict {
+		if f, rng, ok := v.unavailableDependentBody(block, bSchema, featureAddrs); ok {
+			return append(diags, v.unavailableFeatureDiagnostic(f, rng))
+		}
+	}
+	if bodySchema == nil {
+		return diags
+	}
+	if bodySchema.IsDeprecated && !bSchema.IsDeprecated {
+		diags = append(diags, deprecatedBlockDiagnostic(block))
+	}
+
+	bodyAddrs := make([]string, 0, len(featureAddrs))
+	bodyAddrs = append(bodyAddrs, featureAddrs...)
+	bodyAddrs = append(bodyAddrs, dependentFeatureAddresses(block, bSchema, featureAddrs)...)
+
+	return append(diags, v.validateBody(block.Body, bodySchema, bodyAddrs, strict, true)...)
+}
+
+func validateAttribute(attr *hcl.Attribute, aSchema *schema.AttributeSchema) hcl.Diagnostics {
+	if aSchema == nil {
+		return nil
+	}
+
+	if aSchema.IsComputed && !aSchema.IsOptional && !aSchema.IsRequired {
+		return hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Value for unconfigurable attribute",
+				Detail: fmt.Sprintf("Can't configure a value for %q: its value will be decided "+
+					"automatically based on the result of applying this configuration.", attr.Name),
+				Subject: attr.NameRange.Ptr(),
+			},
+		}
+	}
+
+	var diags hcl.Diagnostics
+	if aSchema.IsDeprecated {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Argument is deprecated",
+			Detail:   fmt.Sprintf("The argument %q is deprecated.", attr.Name),
+			Subject:  attr.NameRange.Ptr(),
+		})
+	}
+
+	return append(diags, validateAttributeValue(attr, aSchema)...)
+}
+
+// validateAttributeValue checks literal values against the type(s)
+// of the attribute. Values which depend on references or function
+// calls are not known statically and are therefore not checked.
+func validateAttributeValue(attr *hcl.Attribute, aSchema *schema.AttributeSchema) hcl.Diagnostics {
+	types := aSchema.ValueTypes
+	if aSchema.ValueType != cty.NilType {
+		types = schema.ValueTypes{aSchema.ValueType}
+	}
+	if len(types) == 0 || len(attr.Expr.Variables()) > 0 {
+		return nil
+	}
+
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return nil
+	}
+
+	var firstErr error
+	for _, ty := range types {
+		_, err := convert.Convert(val, ty)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	msg := typeexpr.FormatConversionError(firstErr)
+	if len(types) > 1 {
+		msg = fmt.Sprintf("%s required", strings.Join(types.FriendlyNames(), " or "))
+	}
+
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Incorrect attribute value type",
+			Detail:   fmt.Sprintf("Inappropriate value for attribute %q: %s.", attr.Name, msg),
+			Subject:  attr.Expr.Range().Ptr(),
+		},
+	}
+}
+
+func deprecatedBlockDiagnostic(block *hcl.Block) *hcl.Diagnostic {
+	addr := block.Type
+	for _, label := range block.Labels {
+		addr += fmt.Sprintf(" %q", label)
+	}
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Block is deprecated",
+		Detail:   fmt.Sprintf("The %s block is deprecated.", addr),
+		Subject:  block.DefRange.Ptr(),
+	}
+}
+
+// hclBodySchema converts the given schema into one
+// which HCL can decode the body with. Dynamic blocks
+// are allowed in any nested body which has blocks.
+func hclBodySchema(bs *schema.BodySchema, nested bool) *hcl.BodySchema {
+	hclSchema := &hcl.BodySchema{}
+
+	for _, name := range sortedAttributeSchemaNames(bs.Attributes) {
+		hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{
+			Name:     name,
+			Required: bs.Attributes[name].IsRequired,
+		})
+	}
+
+	for _, bType := range sortedBlockTypes(bs.Blocks) {
+		labelNames := make([]string, len(bs.Blocks[bType].Labels))
+		for i, label := range bs.Blocks[bType].Labels {
+			labelNames[i] = label.Name
+		}
+		hclSchema.Blocks = append(hclSchema.Blocks, hcl.BlockHeaderSchema{
+			Type:       bType,
+			LabelNames: labelNames,
+		})
+	}
+
+	if _, ok := bs.Blocks["dynamic"]; nested && len(bs.Blocks) > 0 && !ok {
+		hclSchema.Blocks = append(hclSchema.Blocks, hcl.BlockHeaderSchema{
+			Type:       "dynamic",
+			LabelNames: []string{"type"},
+		})
+	}
+
+	return hclSchema
+}
+
+func sortedAttributeNames(attrs hcl.Attributes) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedAttributeSchemaNames(attrs map[string]*schema.AttributeSchema) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedBlockTypes(blocks map[string]*schema.BlockSchema) []string {
+	types := make([]string, 0, len(blocks))
+	for bType := range blocks {
+		types = append(types, bType)
+	}
+	sort.Strings(types)
+	return types
+}
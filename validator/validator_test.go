@@ -0,0 +1,686 @@
+package validator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	tfjson "github.com/hashicorp/terraform-json"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var testSchema = &schema.BodySchema{
+	Blocks: map[string]*schema.BlockSchema{
+		"resource": {
+			Labels: []*schema.LabelSchema{
+				{Name: "type", IsDepKey: true},
+				{Name: "name"},
+			},
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"count": {ValueType: cty.Number, IsOptional: true},
+					"provider": {
+						ValueType:  cty.DynamicPseudoType,
+						IsOptional: true,
+						IsDepKey:   true,
+					},
+				},
+			},
+			DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+				schema.NewSchemaKey(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: "test_instance"},
+					},
+				}): {
+					Attributes: map[string]*schema.AttributeSchema{
+						"name":   {ValueType: cty.String, IsRequired: true},
+						"size":   {ValueType: cty.Number, IsOptional: true},
+						"tags":   {ValueType: cty.Map(cty.String), IsOptional: true},
+						"old":    {ValueType: cty.String, IsOptional: true, IsDeprecated: true},
+						"id":     {ValueType: cty.String, IsComputed: true},
+						"either": {ValueTypes: schema.ValueTypes{cty.Bool, cty.Number}, IsOptional: true},
+					},
+					Blocks: map[string]*schema.BlockSchema{
+						"disk": {
+							Type:     schema.BlockTypeList,
+							MinItems: 1,
+							MaxItems: 2,
+							Body: &schema.BodySchema{
+								Attributes: map[string]*schema.AttributeSchema{
+									"size": {ValueType: cty.Number, IsRequired: true},
+								},
+							},
+						},
+						"legacy": {
+							Type:         schema.BlockTypeList,
+							IsDeprecated: true,
+							Body:         schema.NewBodySchema(),
+						},
+					},
+				},
+				schema.NewSchemaKey(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: "test_instance"},
+					},
+					Attributes: []schema.AttributeDependent{
+						{
+							Name: "provider",
+							Expr: schema.ExpressionValue{
+								Reference: lang.Reference{
+									lang.RootStep{Name: "test"},
+									lang.AttrStep{Name: "west"},
+								},
+							},
+						},
+					},
+				}): {
+					Attributes: map[string]*schema.AttributeSchema{
+						"region": {ValueType: cty.String, IsRequired: true},
+					},
+				},
+			},
+		},
+		"backend": {
+			Labels: []*schema.LabelSchema{
+				{Name: "type", IsDepKey: true},
+			},
+			DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+				schema.NewSchemaKey(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: "old"},
+					},
+				}): {
+					IsDeprecated: true,
+					Attributes: map[string]*schema.AttributeSchema{
+						"path": {ValueType: cty.String, IsOptional: true},
+					},
+				},
+			},
+		},
+		"locals": {},
+	},
+}
+
+func TestValidator_ValidateBody(t *testing.T) {
+	testCases := []struct {
+		name          string
+		src           string
+		expectedDiags hcl.Diagnostics
+	}{
+		{
+			"valid",
+			`resource "test_instance" "foo" {
+  count = 2
+  name  = "foo-${count.index}"
+  size  = "10"
+  tags  = { env = "dev" }
+  disk {
+    size = 10
+  }
+}
+locals {
+  anything = "goes"
+}
+`,
+			nil,
+		},
+		{
+			"unknown resource type",
+			`resource "other_instance" "foo" {
+  count   = 2
+  unknown = true
+  nested {}
+}
+`,
+			nil,
+		},
+		{
+			"unknown attribute",
+			`resource "test_instance" "foo" {
+  name    = "foo"
+  unknown = true
+  disk {
+    size = 10
+  }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported argument",
+					Detail:   `An argument named "unknown" is not expected here.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3, Byte: 53},
+						End:      hcl.Pos{Line: 3, Column: 10, Byte: 60},
+					},
+				},
+			},
+		},
+		{
+			"missing required attribute",
+			`resource "test_instance" "foo" {
+  disk {
+  }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Missing required argument",
+					Detail:   `The argument "name" is required, but no definition was found.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+						End:      hcl.Pos{Line: 1, Column: 32, Byte: 31},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Missing required argument",
+					Detail:   `The argument "size" is required, but no definition was found.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 8, Byte: 40},
+						End:      hcl.Pos{Line: 2, Column: 8, Byte: 40},
+					},
+				},
+			},
+		},
+		{
+			"too few blocks",
+			`resource "test_instance" "foo" {
+  name = "foo"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Insufficient disk blocks",
+					Detail:   `At least 1 "disk" blocks are required.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+						End:      hcl.Pos{Line: 1, Column: 32, Byte: 31},
+					},
+				},
+			},
+		},
+		{
+			"too many blocks",
+			`resource "test_instance" "foo" {
+  name = "foo"
+  disk { size = 1 }
+  disk { size = 2 }
+  disk { size = 3 }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Too many disk blocks",
+					Detail:   `No more than 2 "disk" blocks are allowed.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3, Byte: 90},
+						End:      hcl.Pos{Line: 5, Column: 7, Byte: 94},
+					},
+				},
+			},
+		},
+		{
+			"dynamic blocks",
+			`resource "test_instance" "foo" {
+  name = "foo"
+  dynamic "disk" {
+    for_each = var.disks
+    content {
+      size = disk.value
+    }
+  }
+  dynamic "unknown" {
+    for_each = var.disks
+    content {}
+  }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported block type",
+					Detail:   `Blocks of type "unknown" are not expected here, so they cannot be generated dynamically.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 9, Column: 11, Byte: 150},
+						End:      hcl.Pos{Line: 9, Column: 20, Byte: 159},
+					},
+				},
+			},
+		},
+		{
+			"deprecated usage",
+			`resource "test_instance" "foo" {
+  name = "foo"
+  old  = "bar"
+  disk { size = 1 }
+  legacy {}
+}
+backend "old" {
+  path = "foo"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagWarning,
+					Summary:  "Block is deprecated",
+					Detail:   `The backend "old" block is deprecated.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 7, Column: 1, Byte: 97},
+						End:      hcl.Pos{Line: 7, Column: 14, Byte: 110},
+					},
+				},
+				{
+					Severity: hcl.DiagWarning,
+					Summary:  "Argument is deprecated",
+					Detail:   `The argument "old" is deprecated.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3, Byte: 50},
+						End:      hcl.Pos{Line: 3, Column: 6, Byte: 53},
+					},
+				},
+				{
+					Severity: hcl.DiagWarning,
+					Summary:  "Block is deprecated",
+					Detail:   `The legacy block is deprecated.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3, Byte: 85},
+						End:      hcl.Pos{Line: 5, Column: 9, Byte: 91},
+					},
+				},
+			},
+		},
+		{
+			"type mismatch",
+			`resource "test_instance" "foo" {
+  name   = "foo"
+  size   = "large"
+  tags   = { env = ["dev"] }
+  either = "maybe"
+  id     = "i-123"
+  disk { size = 1 }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Incorrect attribute value type",
+					Detail:   `Inappropriate value for attribute "either": bool or number required.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 5, Column: 12, Byte: 109},
+						End:      hcl.Pos{Line: 5, Column: 19, Byte: 116},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Value for unconfigurable attribute",
+					Detail: `Can't configure a value for "id": its value will be decided automatically ` +
+						`based on the result of applying this configuration.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 6, Column: 3, Byte: 119},
+						End:      hcl.Pos{Line: 6, Column: 5, Byte: 121},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Incorrect attribute value type",
+					Detail:   `Inappropriate value for attribute "size": a number is required.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 12, Byte: 61},
+						End:      hcl.Pos{Line: 3, Column: 19, Byte: 68},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Incorrect attribute value type",
+					Detail:   `Inappropriate value for attribute "tags": element "env": string required.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 4, Column: 12, Byte: 80},
+						End:      hcl.Pos{Line: 4, Column: 29, Byte: 97},
+					},
+				},
+			},
+		},
+		{
+			"dependent body keyed on provider",
+			`resource "test_instance" "foo" {
+  provider = test.west
+  name     = "foo"
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Missing required argument",
+					Detail:   `The argument "region" is required, but no definition was found.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+						End:      hcl.Pos{Line: 1, Column: 32, Byte: 31},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported argument",
+					Detail:   `An argument named "name" is not expected here.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3, Byte: 58},
+						End:      hcl.Pos{Line: 3, Column: 7, Byte: 62},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			v := NewValidator(testSchema)
+			diags = v.ValidateFiles(map[string]*hcl.File{
+				"test.tf": f,
+			})
+			if diff := cmp.Diff(tc.expectedDiags, diags); diff != "" {
+				t.Fatalf("diagnostics mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateBody_json(t *testing.T) {
+	src := `{
+  "resource": {
+    "test_instance": {
+      "foo": {
+        "name": "foo",
+        "size": "${var.size}",
+        "unknown": true,
+        "disk": [{"size": 1}]
+      }
+    }
+  }
+}`
+	f, diags := hcljson.Parse([]byte(src), "test.tf.json")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	diags = NewValidator(testSchema).ValidateBody(f.Body)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %s", len(diags), diags)
+	}
+	if diags[0].Summary != "Extraneous JSON object property" {
+		t.Fatalf("unexpected diagnostic: %s", diags[0])
+	}
+}
+
+func TestValidator_ValidateBody_coreSchema(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  count = "two"
+
+  provisioner "local-exec" {
+    command = "echo ${self.id}"
+    unknown = true
+  }
+
+  connection {
+    type        = "winrm"
+    host        = self.public_ip
+    private_key = "foo"
+  }
+}
+
+resource "aws_instance" "db" {
+  connection {
+    host         = self.public_ip
+    bastion_host = "bastion.example.com"
+    agent        = "yes"
+  }
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	coreSchema, err := tfschema.CoreModuleSchemaForVersion(version.Must(version.NewVersion("0.12.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags = NewValidator(coreSchema).ValidateBody(f.Body)
+
+	expectedDetails := []string{
+		`Inappropriate value for attribute "count": a number is required.`,
+		`An argument named "private_key" is not expected here.`,
+		`An argument named "unknown" is not expected here.`,
+		`Inappropriate value for attribute "agent": a bool is required.`,
+	}
+	details := make([]string, len(diags))
+	for i, diag := range diags {
+		details[i] = diag.Detail
+	}
+	if diff := cmp.Diff(expectedDetails, details); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+}
+
+func TestValidator_SetCoreVersion(t *testing.T) {
+	testCases := []struct {
+		version       string
+		src           string
+		expectedDiags hcl.Diagnostics
+	}{
+		{
+			"0.12.0",
+			`module "foo" {
+  source   = "./foo"
+  for_each = var.foos
+}
+variable "bar" {
+  validation {
+    condition     = true
+    error_message = "Invalid."
+  }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unavailable language feature",
+					Detail:   "`for_each` on module blocks requires Terraform >= 0.13.0.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 3, Byte: 38},
+						End:      hcl.Pos{Line: 3, Column: 11, Byte: 46},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unavailable language feature",
+					Detail:   "`validation` block in variable blocks requires Terraform >= 0.12.20.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 6, Column: 3, Byte: 79},
+						End:      hcl.Pos{Line: 6, Column: 13, Byte: 89},
+					},
+				},
+			},
+		},
+		{
+			"0.13.0",
+			`module "foo" {
+  source   = "./foo"
+  for_each = var.foos
+}
+`,
+			nil,
+		},
+		{
+			"0.15.0",
+			`resource "aws_instance" "foo" {
+  provisioner "chef" {
+  }
+}
+`,
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Unavailable language feature",
+					Detail:   "`chef` provisioner was removed in Terraform 0.15.0.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 15, Byte: 46},
+						End:      hcl.Pos{Line: 2, Column: 21, Byte: 52},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.version), func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			v := version.Must(version.NewVersion(tc.version))
+			coreSchema, err := tfschema.CoreModuleSchemaForVersion(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			validator := NewValidator(coreSchema)
+			validator.SetCoreVersion(v)
+			diags = validator.ValidateBody(f.Body)
+			if diff := cmp.Diff(tc.expectedDiags, diags); diff != "" {
+				t.Fatalf("diagnostics mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateBody_partialBackendConfig(t *testing.T) {
+	src := `terraform {
+  backend "s3" {}
+}
+
+terraform {
+  backend "remote" {}
+}
+
+terraform {
+  backend "etcd" {}
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	coreSchema, err := tfschema.CoreModuleSchemaForVersion(version.Must(version.NewVersion("0.14.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags = NewValidator(coreSchema).ValidateBody(f.Body)
+	if len(diags) > 0 {
+		t.Fatalf("expected no diagnostics for partial backend configuration: %s", diags)
+	}
+}
+
+func TestValidator_ValidateBody_remoteStateBackendConfig(t *testing.T) {
+	src := `data "terraform_remote_state" "valid" {
+  backend = "s3"
+  config = {
+    bucket = "mybucket"
+    key    = "network/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "invalid" {
+  backend = "s3"
+  config = {
+    bucket = { a = 1 }
+  }
+}
+
+data "aws_ami" "ubuntu" {
+  backend = "s3"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	v := version.Must(version.NewVersion("0.14.0"))
+	coreSchema, err := tfschema.CoreModuleSchemaForVersion(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := tfschema.NewSchemaMerger(coreSchema)
+	sm.SetCoreVersion(v)
+	sm.SetParsedFiles(map[string]*hcl.File{"main.tf": f})
+	mergedSchema, _, err := sm.MergeWithJsonProviderSchemas(&tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {
+				DataSourceSchemas: map[string]*tfjson.Schema{
+					"aws_ami": {
+						Block: &tfjson.SchemaBlock{
+							Attributes: map[string]*tfjson.SchemaAttribute{
+								"name": {AttributeType: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags = NewValidator(mergedSchema).ValidateBody(f.Body)
+
+	// backend is only a dependency key of terraform_remote_state
+	expectedDetails := []string{
+		`main.tf:11,12-13,4: Inappropriate value for attribute "config": attribute "bucket": string required.`,
+		`main.tf:17,3-10: An argument named "backend" is not expected here.`,
+	}
+	details := make([]string, len(diags))
+	for i, diag := range diags {
+		details[i] = fmt.Sprintf("%s: %s", diag.Subject, diag.Detail)
+	}
+	if diff := cmp.Diff(expectedDetails, details); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+}